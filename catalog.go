@@ -0,0 +1,244 @@
+package problems
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BadRequest returns a new 400 Bad Request problem.
+func BadRequest() *Problem {
+	return NewStatusProblem(http.StatusBadRequest)
+}
+
+// Unauthorized returns a new 401 Unauthorized problem. Each of the provided
+// challenges, such as `Bearer realm="example"`, is written in a
+// WWW-Authenticate header field, which RFC-9110 requires in 401 responses.
+func Unauthorized(challenges ...string) *Problem {
+	p := NewStatusProblem(http.StatusUnauthorized)
+	if len(challenges) > 0 {
+		p.WithHeader("WWW-Authenticate", challenges...)
+	}
+	return p
+}
+
+// PaymentRequired returns a new 402 Payment Required problem.
+func PaymentRequired() *Problem {
+	return NewStatusProblem(http.StatusPaymentRequired)
+}
+
+// Forbidden returns a new 403 Forbidden problem.
+func Forbidden() *Problem {
+	return NewStatusProblem(http.StatusForbidden)
+}
+
+// NotFound returns a new 404 Not Found problem.
+func NotFound() *Problem {
+	return NewStatusProblem(http.StatusNotFound)
+}
+
+// MethodNotAllowed returns a new 405 Method Not Allowed problem. The provided
+// methods are written in the Allow header field, which RFC-9110 requires in
+// 405 responses even when no methods are allowed.
+func MethodNotAllowed(allowed ...string) *Problem {
+	return NewStatusProblem(http.StatusMethodNotAllowed).
+		WithHeader("Allow", strings.Join(allowed, ", "))
+}
+
+// NotAcceptable returns a new 406 Not Acceptable problem.
+func NotAcceptable() *Problem {
+	return NewStatusProblem(http.StatusNotAcceptable)
+}
+
+// ProxyAuthRequired returns a new 407 Proxy Authentication Required problem.
+// Each of the provided challenges is written in a Proxy-Authenticate header
+// field, which RFC-9110 requires in 407 responses.
+func ProxyAuthRequired(challenges ...string) *Problem {
+	p := NewStatusProblem(http.StatusProxyAuthRequired)
+	if len(challenges) > 0 {
+		p.WithHeader("Proxy-Authenticate", challenges...)
+	}
+	return p
+}
+
+// RequestTimeout returns a new 408 Request Timeout problem.
+func RequestTimeout() *Problem {
+	return NewStatusProblem(http.StatusRequestTimeout)
+}
+
+// Conflict returns a new 409 Conflict problem.
+func Conflict() *Problem {
+	return NewStatusProblem(http.StatusConflict)
+}
+
+// Gone returns a new 410 Gone problem.
+func Gone() *Problem {
+	return NewStatusProblem(http.StatusGone)
+}
+
+// LengthRequired returns a new 411 Length Required problem.
+func LengthRequired() *Problem {
+	return NewStatusProblem(http.StatusLengthRequired)
+}
+
+// PreconditionFailed returns a new 412 Precondition Failed problem.
+func PreconditionFailed() *Problem {
+	return NewStatusProblem(http.StatusPreconditionFailed)
+}
+
+// RequestEntityTooLarge returns a new 413 Request Entity Too Large problem.
+func RequestEntityTooLarge() *Problem {
+	return NewStatusProblem(http.StatusRequestEntityTooLarge)
+}
+
+// RequestURITooLong returns a new 414 Request URI Too Long problem.
+func RequestURITooLong() *Problem {
+	return NewStatusProblem(http.StatusRequestURITooLong)
+}
+
+// UnsupportedMediaType returns a new 415 Unsupported Media Type problem. If
+// any media types are provided, they are written in the Accept header field
+// to tell the client which media types are supported.
+func UnsupportedMediaType(accepted ...string) *Problem {
+	p := NewStatusProblem(http.StatusUnsupportedMediaType)
+	if len(accepted) > 0 {
+		p.WithHeader("Accept", strings.Join(accepted, ", "))
+	}
+	return p
+}
+
+// RequestedRangeNotSatisfiable returns a new 416 Requested Range Not
+// Satisfiable problem. The provided size of the selected representation is
+// written in the Content-Range header field, as recommended by RFC-9110.
+func RequestedRangeNotSatisfiable(size int64) *Problem {
+	return NewStatusProblem(http.StatusRequestedRangeNotSatisfiable).
+		WithHeader("Content-Range", "bytes */"+strconv.FormatInt(size, 10))
+}
+
+// ExpectationFailed returns a new 417 Expectation Failed problem.
+func ExpectationFailed() *Problem {
+	return NewStatusProblem(http.StatusExpectationFailed)
+}
+
+// Teapot returns a new 418 I'm a teapot problem.
+func Teapot() *Problem {
+	return NewStatusProblem(http.StatusTeapot)
+}
+
+// MisdirectedRequest returns a new 421 Misdirected Request problem.
+func MisdirectedRequest() *Problem {
+	return NewStatusProblem(http.StatusMisdirectedRequest)
+}
+
+// UnprocessableEntity returns a new 422 Unprocessable Entity problem. See
+// NewValidationProblem for a problem which also describes each of the reasons
+// that a request is invalid.
+func UnprocessableEntity() *Problem {
+	return NewStatusProblem(http.StatusUnprocessableEntity)
+}
+
+// Locked returns a new 423 Locked problem.
+func Locked() *Problem {
+	return NewStatusProblem(http.StatusLocked)
+}
+
+// FailedDependency returns a new 424 Failed Dependency problem.
+func FailedDependency() *Problem {
+	return NewStatusProblem(http.StatusFailedDependency)
+}
+
+// TooEarly returns a new 425 Too Early problem.
+func TooEarly() *Problem {
+	return NewStatusProblem(http.StatusTooEarly)
+}
+
+// UpgradeRequired returns a new 426 Upgrade Required problem. The provided
+// protocols are written in the Upgrade header field, which RFC-9110 requires
+// to list at least one protocol in 426 responses.
+func UpgradeRequired(protocol string, protocols ...string) *Problem {
+	return NewStatusProblem(http.StatusUpgradeRequired).
+		WithHeader("Upgrade", strings.Join(append([]string{protocol}, protocols...), ", "))
+}
+
+// PreconditionRequired returns a new 428 Precondition Required problem.
+func PreconditionRequired() *Problem {
+	return NewStatusProblem(http.StatusPreconditionRequired)
+}
+
+// TooManyRequests returns a new 429 Too Many Requests problem. If retryAfter
+// is positive, it is written in the Retry-After header field to tell the
+// client how long to wait before making a new request.
+func TooManyRequests(retryAfter time.Duration) *Problem {
+	return NewStatusProblem(http.StatusTooManyRequests).WithRetryAfter(retryAfter)
+}
+
+// RequestHeaderFieldsTooLarge returns a new 431 Request Header Fields Too
+// Large problem.
+func RequestHeaderFieldsTooLarge() *Problem {
+	return NewStatusProblem(http.StatusRequestHeaderFieldsTooLarge)
+}
+
+// UnavailableForLegalReasons returns a new 451 Unavailable For Legal Reasons
+// problem.
+func UnavailableForLegalReasons() *Problem {
+	return NewStatusProblem(http.StatusUnavailableForLegalReasons)
+}
+
+// InternalServerError returns a new 500 Internal Server Error problem.
+func InternalServerError() *Problem {
+	return NewStatusProblem(http.StatusInternalServerError)
+}
+
+// NotImplemented returns a new 501 Not Implemented problem.
+func NotImplemented() *Problem {
+	return NewStatusProblem(http.StatusNotImplemented)
+}
+
+// BadGateway returns a new 502 Bad Gateway problem.
+func BadGateway() *Problem {
+	return NewStatusProblem(http.StatusBadGateway)
+}
+
+// ServiceUnavailable returns a new 503 Service Unavailable problem. If
+// retryAfter is positive, it is written in the Retry-After header field to tell
+// the client how long the service is expected to be unavailable.
+func ServiceUnavailable(retryAfter time.Duration) *Problem {
+	return NewStatusProblem(http.StatusServiceUnavailable).WithRetryAfter(retryAfter)
+}
+
+// GatewayTimeout returns a new 504 Gateway Timeout problem.
+func GatewayTimeout() *Problem {
+	return NewStatusProblem(http.StatusGatewayTimeout)
+}
+
+// HTTPVersionNotSupported returns a new 505 HTTP Version Not Supported problem.
+func HTTPVersionNotSupported() *Problem {
+	return NewStatusProblem(http.StatusHTTPVersionNotSupported)
+}
+
+// VariantAlsoNegotiates returns a new 506 Variant Also Negotiates problem.
+func VariantAlsoNegotiates() *Problem {
+	return NewStatusProblem(http.StatusVariantAlsoNegotiates)
+}
+
+// InsufficientStorage returns a new 507 Insufficient Storage problem.
+func InsufficientStorage() *Problem {
+	return NewStatusProblem(http.StatusInsufficientStorage)
+}
+
+// LoopDetected returns a new 508 Loop Detected problem.
+func LoopDetected() *Problem {
+	return NewStatusProblem(http.StatusLoopDetected)
+}
+
+// NotExtended returns a new 510 Not Extended problem.
+func NotExtended() *Problem {
+	return NewStatusProblem(http.StatusNotExtended)
+}
+
+// NetworkAuthenticationRequired returns a new 511 Network Authentication
+// Required problem.
+func NetworkAuthenticationRequired() *Problem {
+	return NewStatusProblem(http.StatusNetworkAuthenticationRequired)
+}
@@ -0,0 +1,132 @@
+package problems
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCatalog(t *testing.T) {
+	tests := []struct {
+		name         string
+		problem      *Problem
+		expectStatus int
+		expectHeader http.Header
+	}{
+		{name: "BadRequest", problem: BadRequest(), expectStatus: http.StatusBadRequest},
+		{
+			name:         "Unauthorized",
+			problem:      Unauthorized(`Bearer realm="example"`, `Basic realm="example"`),
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: http.Header{"Www-Authenticate": {`Bearer realm="example"`, `Basic realm="example"`}},
+		},
+		{name: "NotFound", problem: NotFound(), expectStatus: http.StatusNotFound},
+		{
+			name:         "MethodNotAllowed",
+			problem:      MethodNotAllowed(http.MethodGet, http.MethodHead),
+			expectStatus: http.StatusMethodNotAllowed,
+			expectHeader: http.Header{"Allow": {"GET, HEAD"}},
+		},
+		{
+			name:         "MethodNotAllowed with no allowed methods",
+			problem:      MethodNotAllowed(),
+			expectStatus: http.StatusMethodNotAllowed,
+			expectHeader: http.Header{"Allow": {""}},
+		},
+		{
+			name:         "ProxyAuthRequired",
+			problem:      ProxyAuthRequired(`Basic realm="proxy"`),
+			expectStatus: http.StatusProxyAuthRequired,
+			expectHeader: http.Header{"Proxy-Authenticate": {`Basic realm="proxy"`}},
+		},
+		{
+			name:         "UnsupportedMediaType",
+			problem:      UnsupportedMediaType("application/json", "application/xml"),
+			expectStatus: http.StatusUnsupportedMediaType,
+			expectHeader: http.Header{"Accept": {"application/json, application/xml"}},
+		},
+		{name: "UnsupportedMediaType with no media types", problem: UnsupportedMediaType(), expectStatus: http.StatusUnsupportedMediaType},
+		{
+			name:         "RequestedRangeNotSatisfiable",
+			problem:      RequestedRangeNotSatisfiable(1024),
+			expectStatus: http.StatusRequestedRangeNotSatisfiable,
+			expectHeader: http.Header{"Content-Range": {"bytes */1024"}},
+		},
+		{name: "Teapot", problem: Teapot(), expectStatus: http.StatusTeapot},
+		{
+			name:         "UpgradeRequired",
+			problem:      UpgradeRequired("HTTP/3.0"),
+			expectStatus: http.StatusUpgradeRequired,
+			expectHeader: http.Header{"Upgrade": {"HTTP/3.0"}},
+		},
+		{
+			name:         "UpgradeRequired with several protocols",
+			problem:      UpgradeRequired("HTTP/2.0", "HTTP/3.0"),
+			expectStatus: http.StatusUpgradeRequired,
+			expectHeader: http.Header{"Upgrade": {"HTTP/2.0, HTTP/3.0"}},
+		},
+		{
+			name:         "TooManyRequests",
+			problem:      TooManyRequests(1500 * time.Millisecond),
+			expectStatus: http.StatusTooManyRequests,
+			expectHeader: http.Header{"Retry-After": {"2"}},
+		},
+		{name: "TooManyRequests with no delay", problem: TooManyRequests(0), expectStatus: http.StatusTooManyRequests},
+		{name: "UnavailableForLegalReasons", problem: UnavailableForLegalReasons(), expectStatus: http.StatusUnavailableForLegalReasons},
+		{name: "InternalServerError", problem: InternalServerError(), expectStatus: http.StatusInternalServerError},
+		{
+			name:         "ServiceUnavailable",
+			problem:      ServiceUnavailable(time.Minute),
+			expectStatus: http.StatusServiceUnavailable,
+			expectHeader: http.Header{"Retry-After": {"60"}},
+		},
+		{name: "NetworkAuthenticationRequired", problem: NetworkAuthenticationRequired(), expectStatus: http.StatusNetworkAuthenticationRequired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.problem.Type != DefaultURL || test.problem.Status != test.expectStatus || test.problem.Title != http.StatusText(test.expectStatus) {
+				t.Errorf("expected an about:blank %d problem, got %s", test.expectStatus, test.problem)
+			}
+
+			handlers := map[string]http.HandlerFunc{
+				"ProblemHandler":    ProblemHandler(test.problem),
+				"XMLProblemHandler": XMLProblemHandler(test.problem),
+				"Writer":            (&Writer{}).Handler(test.problem),
+			}
+			for name, handler := range handlers {
+				w := httptest.NewRecorder()
+				handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+				// The recorded result only contains the header fields which
+				// were set before WriteHeader was called.
+				resp := w.Result()
+				if resp.StatusCode != test.expectStatus {
+					t.Errorf("%s: expected status %d, got %d", name, test.expectStatus, resp.StatusCode)
+				}
+				for field, values := range test.expectHeader {
+					if got := resp.Header.Values(field); !reflect.DeepEqual(got, values) {
+						t.Errorf("%s: expected %s header %q, got %q", name, field, values, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCatalog_Immutable(t *testing.T) {
+	first := MethodNotAllowed(http.MethodGet).WithDetail("first")
+	second := MethodNotAllowed(http.MethodPost)
+
+	if second.Detail != "" {
+		t.Errorf("expected each problem to be independent, got detail %q", second.Detail)
+	}
+	if got := second.Header().Get("Allow"); got != http.MethodPost {
+		t.Errorf("expected each problem to have its own header, got %q", got)
+	}
+	if got := first.Header().Get("Allow"); got != http.MethodGet {
+		t.Errorf("expected each problem to have its own header, got %q", got)
+	}
+}
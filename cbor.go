@@ -0,0 +1,641 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// The keys of the standard problem detail entries defined by RFC-9290.
+const (
+	cborTitle        = -1
+	cborDetail       = -2
+	cborInstance     = -3
+	cborResponseCode = -4
+)
+
+// The key of the custom problem detail entry defined by Appendix A of RFC-9290,
+// which carries the members of an RFC-9457 problem that have no standard key,
+// and the keys of the type and status members within it.
+const (
+	cborRFC7807 = 7807
+	cborType    = 0
+	cborStatus  = 1
+)
+
+// The CBOR major types, as defined by section 3.1 of RFC-8949.
+const (
+	cborUint   byte = 0
+	cborNegint byte = 1
+	cborBytes  byte = 2
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborTag    byte = 6
+	cborSimple byte = 7
+)
+
+// maxCBORDepth is the maximum depth of nested arrays, maps and tags accepted
+// by UnmarshalCBOR.
+const maxCBORDepth = 1000
+
+// MarshalCBOR returns the Concise Problem Details encoding of p, as defined by
+// RFC-9290.
+//
+// The title, detail and instance members are written using their standard
+// keys, while the type, status and extension members are written to the
+// custom problem detail entry defined by Appendix A of RFC-9290. The problem
+// is first serialized as JSON, so that the same member names are used by both
+// formats.
+//
+// Problem does not implement a MarshalCBOR method itself, for the same reason
+// that it does not implement json.Marshaler, so that types which embed it
+// continue to serialize their own fields. Such types may use this function
+// instead.
+func MarshalCBOR(p Details) ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var standard, custom []jsonMember
+	for _, m := range jsonMembers(data) {
+		switch m.name {
+		case "title", "detail", "instance":
+			standard = append(standard, m)
+		default:
+			custom = append(custom, m)
+		}
+	}
+
+	entries := len(standard)
+	if len(custom) > 0 {
+		entries++
+	}
+
+	var e cborEncoder
+	e.writeHead(cborMap, uint64(entries))
+	for _, m := range standard {
+		switch m.name {
+		case "title":
+			e.writeInt(cborTitle)
+		case "detail":
+			e.writeInt(cborDetail)
+		case "instance":
+			e.writeInt(cborInstance)
+		}
+		if err := e.encodeJSON(m.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(custom) > 0 {
+		e.writeInt(cborRFC7807)
+		e.writeHead(cborMap, uint64(len(custom)))
+		for _, m := range custom {
+			switch m.name {
+			case "type":
+				e.writeInt(cborType)
+			case "status":
+				e.writeInt(cborStatus)
+			default:
+				e.writeText(m.name)
+			}
+			if err := e.encodeJSON(m.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return e.Bytes(), nil
+}
+
+// UnmarshalCBOR decodes the Concise Problem Details encoding of a problem, as
+// defined by RFC-9290, into v.
+//
+// The problem is first translated into its JSON representation, and is then
+// decoded with json.Unmarshal, so that v may be any type which can be decoded
+// from problem+json. If the problem has no status, but has a CoAP response
+// code, the status is set to the equivalent HTTP status code.
+func UnmarshalCBOR(data []byte, v any) error {
+	d := cborDecoder{data: data}
+	item, err := d.decode()
+	if err != nil {
+		return err
+	}
+	if d.off != len(data) {
+		return fmt.Errorf("%w: unexpected data after top-level item", ErrInvalidCBOR)
+	}
+
+	entries, ok := item.(cborPairs)
+	if !ok {
+		return fmt.Errorf("%w: problem details must be a map", ErrInvalidCBOR)
+	}
+
+	var (
+		members      = map[string]any{}
+		order        []string
+		responseCode any
+	)
+	set := func(name string, value any) {
+		if _, ok := members[name]; !ok {
+			order = append(order, name)
+		}
+		members[name] = value
+	}
+
+	for _, entry := range entries {
+		key, ok := cborIntKey(entry.key)
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case cborTitle:
+			set("title", entry.value)
+		case cborDetail:
+			set("detail", entry.value)
+		case cborInstance:
+			set("instance", entry.value)
+		case cborResponseCode:
+			responseCode = entry.value
+		case cborRFC7807:
+			custom, ok := entry.value.(cborPairs)
+			if !ok {
+				return fmt.Errorf("%w: RFC 7807 problem detail entry must be a map", ErrInvalidCBOR)
+			}
+			for _, m := range custom {
+				if name, ok := m.key.(string); ok {
+					set(name, m.value)
+					continue
+				}
+
+				switch key, _ := cborIntKey(m.key); key {
+				case cborType:
+					set("type", m.value)
+				case cborStatus:
+					set("status", m.value)
+				}
+			}
+		}
+	}
+
+	if _, ok := members["status"]; !ok && responseCode != nil {
+		set("status", httpStatus(responseCode))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range order {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := writeCBORJSON(&buf, members[name]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+
+	return json.Unmarshal(buf.Bytes(), v)
+}
+
+// MarshalCBOR returns the Concise Problem Details encoding of the problem, in
+// the same way as the MarshalCBOR function. It allows an ExtendedProblem to be
+// used with CBOR libraries which recognize the MarshalCBOR method.
+func (p *ExtendedProblem[T]) MarshalCBOR() ([]byte, error) {
+	return MarshalCBOR(p)
+}
+
+// UnmarshalCBOR decodes the Concise Problem Details encoding of a problem into
+// p, in the same way as the UnmarshalCBOR function. It allows an
+// ExtendedProblem to be used with CBOR libraries which recognize the
+// UnmarshalCBOR method.
+func (p *ExtendedProblem[T]) UnmarshalCBOR(data []byte) error {
+	return UnmarshalCBOR(data, p)
+}
+
+// cborIntKey returns the value of a decoded map key, if it is an integer.
+func cborIntKey(key any) (int64, bool) {
+	n, ok := key.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := n.Int64()
+	return i, err == nil
+}
+
+// httpStatus returns the HTTP status code equivalent to the provided CoAP
+// response code, such as 404 for 4.04 (132). The code is returned unchanged
+// if it is not an unsigned integer.
+func httpStatus(code any) any {
+	n, ok := code.(json.Number)
+	if !ok {
+		return code
+	}
+	c, err := strconv.ParseUint(string(n), 10, 8)
+	if err != nil {
+		return code
+	}
+	return json.Number(strconv.FormatUint((c>>5)*100+(c&0x1f), 10))
+}
+
+// A cborEncoder writes CBOR data items to its buffer, using the preferred
+// serialization described by section 4.1 of RFC-8949.
+type cborEncoder struct {
+	bytes.Buffer
+}
+
+// writeHead writes the initial bytes of a data item with the provided major
+// type and argument.
+func (e *cborEncoder) writeHead(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		e.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		e.Write([]byte{major | 24, byte(n)})
+	case n <= math.MaxUint16:
+		e.WriteByte(major | 25)
+		e.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		e.WriteByte(major | 26)
+		e.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		e.WriteByte(major | 27)
+		e.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// writeInt writes n as an unsigned or negative integer.
+func (e *cborEncoder) writeInt(n int64) {
+	if n < 0 {
+		e.writeHead(cborNegint, uint64(-1-n))
+		return
+	}
+	e.writeHead(cborUint, uint64(n))
+}
+
+// writeText writes s as a text string.
+func (e *cborEncoder) writeText(s string) {
+	e.writeHead(cborText, uint64(len(s)))
+	e.WriteString(s)
+}
+
+// writeFloat writes f as a single-precision float if it can be represented
+// exactly by one, or as a double-precision float otherwise.
+func (e *cborEncoder) writeFloat(f float64) {
+	if float64(float32(f)) == f {
+		e.WriteByte(cborSimple<<5 | 26)
+		e.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(f))))
+		return
+	}
+	e.WriteByte(cborSimple<<5 | 27)
+	e.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+}
+
+// encodeJSON writes the provided JSON value as the equivalent CBOR data item.
+// Objects are written as maps with text string keys, in the same order as
+// their members.
+func (e *cborEncoder) encodeJSON(value json.RawMessage) error {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return fmt.Errorf("%w: empty JSON value", ErrInvalidCBOR)
+	}
+
+	switch value[0] {
+	case '{':
+		members := jsonMembers(value)
+		e.writeHead(cborMap, uint64(len(members)))
+		for _, m := range members {
+			e.writeText(m.name)
+			if err := e.encodeJSON(m.value); err != nil {
+				return err
+			}
+		}
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			return err
+		}
+		e.writeHead(cborArray, uint64(len(items)))
+		for _, item := range items {
+			if err := e.encodeJSON(item); err != nil {
+				return err
+			}
+		}
+	case '"':
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return err
+		}
+		e.writeText(s)
+	case 't':
+		e.WriteByte(cborSimple<<5 | 21)
+	case 'f':
+		e.WriteByte(cborSimple<<5 | 20)
+	case 'n':
+		e.WriteByte(cborSimple<<5 | 22)
+	default:
+		num := string(value)
+		if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+			e.writeInt(n)
+		} else if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+			e.writeHead(cborUint, n)
+		} else if f, err := strconv.ParseFloat(num, 64); err == nil {
+			e.writeFloat(f)
+		} else {
+			return fmt.Errorf("%w: unsupported JSON number %s", ErrInvalidCBOR, num)
+		}
+	}
+	return nil
+}
+
+// A cborPair is a single entry of a decoded CBOR map.
+type cborPair struct {
+	key   any
+	value any
+}
+
+// cborPairs is a decoded CBOR map, with its entries kept in their encoded
+// order.
+type cborPairs []cborPair
+
+// A cborDecoder decodes CBOR data items into generic Go values. Integers and
+// floats are decoded as json.Number, maps as cborPairs, and arrays as []any.
+// Tags are ignored, and their content is decoded in their place.
+type cborDecoder struct {
+	data  []byte
+	off   int
+	depth int
+}
+
+// cborBreak is the "break" stop code which ends an indefinite-length item.
+const cborBreak = 0xff
+
+// readBreak reports whether the next byte is a "break" stop code, and consumes
+// it if so.
+func (d *cborDecoder) readBreak() bool {
+	if d.off < len(d.data) && d.data[d.off] == cborBreak {
+		d.off++
+		return true
+	}
+	return false
+}
+
+// readHead reads the initial bytes of a data item, and returns its major type
+// and argument. The indefinite flag is set if the item has an indefinite
+// length, or is a "break" stop code.
+func (d *cborDecoder) readHead() (major byte, info byte, n uint64, indefinite bool, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, false, io.ErrUnexpectedEOF
+	}
+	major, info = d.data[d.off]>>5, d.data[d.off]&0x1f
+	d.off++
+
+	size := 0
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	case info == 31 && major >= cborBytes && major != cborTag:
+		return major, info, 0, true, nil
+	default:
+		return 0, 0, 0, false, fmt.Errorf("%w: malformed initial byte %#x", ErrInvalidCBOR, d.data[d.off-1])
+	}
+
+	if len(d.data)-d.off < size {
+		return 0, 0, 0, false, io.ErrUnexpectedEOF
+	}
+	for _, b := range d.data[d.off : d.off+size] {
+		n = n<<8 | uint64(b)
+	}
+	d.off += size
+	return major, info, n, false, nil
+}
+
+// decode reads and decodes the next data item.
+func (d *cborDecoder) decode() (any, error) {
+	major, info, n, indefinite, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		return json.Number(strconv.FormatUint(n, 10)), nil
+	case cborNegint:
+		neg := new(big.Int).SetUint64(n)
+		return json.Number(neg.Neg(neg.Add(neg, big.NewInt(1))).String()), nil
+	case cborBytes, cborText:
+		b, err := d.readString(major, n, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(b), nil
+		}
+		return b, nil
+	case cborArray, cborMap, cborTag:
+		if d.depth++; d.depth > maxCBORDepth {
+			return nil, fmt.Errorf("%w: exceeded max depth", ErrInvalidCBOR)
+		}
+		defer func() { d.depth-- }()
+
+		switch major {
+		case cborArray:
+			return d.decodeArray(n, indefinite)
+		case cborMap:
+			return d.decodeMap(n, indefinite)
+		default:
+			return d.decode()
+		}
+	default:
+		return d.decodeSimple(info, n, indefinite)
+	}
+}
+
+// readString reads the content of a byte or text string. The chunks of an
+// indefinite-length string are concatenated.
+func (d *cborDecoder) readString(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		if uint64(len(d.data)-d.off) < n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		b := d.data[d.off : d.off+int(n)]
+		d.off += int(n)
+		return b, nil
+	}
+
+	var buf []byte
+	for {
+		if d.readBreak() {
+			return buf, nil
+		}
+
+		chunkMajor, _, n, indefinite, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || indefinite {
+			return nil, fmt.Errorf("%w: malformed indefinite-length string", ErrInvalidCBOR)
+		}
+
+		chunk, err := d.readString(major, n, false)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, chunk...)
+	}
+}
+
+// decodeArray decodes the items of an array of length n.
+func (d *cborDecoder) decodeArray(n uint64, indefinite bool) ([]any, error) {
+	if !indefinite && uint64(len(d.data)-d.off) < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	items := []any{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite && d.readBreak() {
+			break
+		}
+
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// decodeMap decodes the entries of a map of length n.
+func (d *cborDecoder) decodeMap(n uint64, indefinite bool) (cborPairs, error) {
+	if !indefinite && uint64(len(d.data)-d.off)/2 < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	pairs := cborPairs{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		if indefinite && d.readBreak() {
+			break
+		}
+
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, cborPair{key: key, value: value})
+	}
+	return pairs, nil
+}
+
+// decodeSimple decodes a simple value or float. A "break" stop code is only
+// valid at the end of an indefinite-length item, and is rejected.
+func (d *cborDecoder) decodeSimple(info byte, n uint64, indefinite bool) (any, error) {
+	var f float64
+	switch {
+	case indefinite:
+		return nil, fmt.Errorf("%w: unexpected break", ErrInvalidCBOR)
+	case info == 20:
+		return false, nil
+	case info == 21:
+		return true, nil
+	case info == 22, info == 23:
+		return nil, nil
+	case info == 25:
+		f = halfToFloat64(uint16(n))
+	case info == 26:
+		f = float64(math.Float32frombits(uint32(n)))
+	case info == 27:
+		f = math.Float64frombits(n)
+	default:
+		return nil, fmt.Errorf("%w: unsupported simple value %d", ErrInvalidCBOR, n)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%w: unsupported float value %v", ErrInvalidCBOR, f)
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float to a float64, as
+// described by Appendix D of RFC-8949.
+func halfToFloat64(half uint16) float64 {
+	exp, mant := int(half>>10)&0x1f, float64(half&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if half&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
+
+// writeCBORJSON writes a value decoded by a cborDecoder to buf as JSON. Byte
+// strings are written as base64 encoded strings, in the same way as []byte
+// values are by encoding/json.
+func writeCBORJSON(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case cborPairs:
+		buf.WriteByte('{')
+		for i, pair := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, ok := pair.key.(string)
+			if !ok {
+				return fmt.Errorf("%w: extension member names must be text strings", ErrInvalidCBOR)
+			}
+			key, _ := json.Marshal(name)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeCBORJSON(buf, pair.value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCBORJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
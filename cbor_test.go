@@ -0,0 +1,278 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cborHex decodes a hex encoded CBOR data item, ignoring any whitespace.
+func cborHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatalf("invalid hex: %s", err)
+	}
+	return data
+}
+
+func TestMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem Details
+		expect  string
+	}{
+		{
+			name:    "should encode standard members with integer keys",
+			problem: NewDetailedProblem(http.StatusNotFound, "gone").WithInstance("/x"),
+			expect: `a4
+				20 69 4e6f7420466f756e64
+				21 64 676f6e65
+				22 62 2f78
+				19 1e7f a2
+					00 6b 61626f75743a626c616e6b
+					01 19 0194`,
+		},
+		{
+			name: "should encode extension members in the RFC 7807 entry",
+			problem: NewExt[map[string]any]().
+				WithTitle("Out of credit").
+				WithExtension(map[string]any{"balance": 30.5, "accounts": []string{"a"}, "ok": true, "next": nil}),
+			expect: `a2
+				20 6d 4f7574206f6620637265646974
+				19 1e7f a5
+					00 6b 61626f75743a626c616e6b
+					68 6163636f756e7473 81 61 61
+					67 62616c616e6365 fa 41f40000
+					64 6e657874 f6
+					62 6f6b f5`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := MarshalCBOR(test.problem)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if expect := cborHex(t, test.expect); !bytes.Equal(data, expect) {
+				t.Errorf("unexpected encoding:\ngot  %x\nwant %x", data, expect)
+			}
+		})
+	}
+}
+
+func TestMarshalCBOR_Error(t *testing.T) {
+	problem := NewExt[[]string]().WithExtension([]string{"a"})
+	if _, err := MarshalCBOR(problem); !errors.Is(err, ErrExtensionsMustBeObject) {
+		t.Errorf("expected ErrExtensionsMustBeObject, got %v", err)
+	}
+}
+
+func TestUnmarshalCBOR(t *testing.T) {
+	original := NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusForbidden).
+		WithDetail("Your current balance is 30, but that costs 50.").
+		WithInstance("/account/12345/msgs/abc").
+		WithExtension(creditProblemExt{Balance: -30.25, Accounts: []string{"/account/12345", "/account/67890"}})
+
+	data, err := MarshalCBOR(original)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var decoded ExtendedProblem[creditProblemExt]
+	if err := UnmarshalCBOR(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(&decoded, original) {
+		t.Errorf("problems were not equal: wanted\n%+v\nbut got\n%+v", original, &decoded)
+	}
+}
+
+func TestExtendedProblem_CBORMethods(t *testing.T) {
+	original := NewExt[creditProblemExt]().
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}})
+
+	var marshaler interface{ MarshalCBOR() ([]byte, error) } = original
+	data, err := marshaler.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if expect, _ := MarshalCBOR(original); !bytes.Equal(data, expect) {
+		t.Errorf("unexpected encoding:\ngot  %x\nwant %x", data, expect)
+	}
+
+	decoded := NewExt[creditProblemExt]()
+	var unmarshaler interface{ UnmarshalCBOR([]byte) error } = decoded
+	if err := unmarshaler.UnmarshalCBOR(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("problems were not equal: wanted\n%+v\nbut got\n%+v", original, decoded)
+	}
+}
+
+func TestUnmarshalCBOR_Encodings(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		expect *Problem
+	}{
+		{
+			name: "should convert CoAP response codes to HTTP statuses",
+			data: `a3
+				20 6b 556e6b6e6f776e206b6579
+				21 78 1a 4b65792034373131206973206e6f742072656769737465726564
+				23 18 84`,
+			expect: New().WithTitle("Unknown key").WithDetail("Key 4711 is not registered").WithStatus(http.StatusNotFound).WithType(""),
+		},
+		{
+			name: "should prefer the status of the RFC 7807 entry",
+			data: `a3
+				20 63 466f6f
+				23 18 a0
+				19 1e7f a1 01 19 01f7`,
+			expect: New().WithTitle("Foo").WithStatus(http.StatusServiceUnavailable).WithType(""),
+		},
+		{
+			name: "should decode indefinite-length items",
+			data: `bf
+				20 7f 63 4e6f74 66 20466f756e64 ff
+				19 1e7f bf 01 19 0194 ff
+				ff`,
+			expect: NewStatusProblem(http.StatusNotFound).WithType(""),
+		},
+		{
+			name: "should ignore unknown entries and tags",
+			data: `a3
+				20 63 466f6f
+				24 d8 20 70 68747470733a2f2f6578616d706c652f
+				19 1e7f a1 00 d8 20 70 68747470733a2f2f6578616d706c652f`,
+			expect: New().WithTitle("Foo").WithType("https://example/"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var problem Problem
+			if err := UnmarshalCBOR(cborHex(t, test.data), &problem); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(&problem, test.expect) {
+				t.Errorf("problems were not equal: wanted\n%+v\nbut got\n%+v", test.expect, &problem)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCBOR_Extensions(t *testing.T) {
+	// {-1: "Foo", 7807: {"half": 1.5, "big": -2^64, "bytes": h'0102', "list": [_ 1, 2]}}
+	data := cborHex(t, `a2
+		20 63 466f6f
+		19 1e7f a4
+			64 68616c66 f9 3e00
+			63 626967 3b ffffffffffffffff
+			65 6279746573 42 0102
+			64 6c697374 9f 01 02 ff`)
+
+	var problem RawProblem
+	if err := UnmarshalCBOR(data, &problem); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expect := map[string]string{
+		"half":  "1.5",
+		"big":   "-18446744073709551616",
+		"bytes": `"AQI="`,
+		"list":  "[1,2]",
+	}
+	for name, value := range expect {
+		if got := string(problem.Extensions[name]); got != value {
+			t.Errorf("expected %s to be %s, got %s", name, value, got)
+		}
+	}
+}
+
+func TestUnmarshalCBOR_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		expect error
+	}{
+		{name: "should reject empty data", data: ``, expect: io.ErrUnexpectedEOF},
+		{name: "should reject truncated data", data: `a1 20 69 4e6f74`, expect: io.ErrUnexpectedEOF},
+		{name: "should reject lengths longer than the data", data: `bb 7fffffffffffffff`, expect: io.ErrUnexpectedEOF},
+		{name: "should reject trailing data", data: `a0 00`, expect: ErrInvalidCBOR},
+		{name: "should reject non-map problems", data: `80`, expect: ErrInvalidCBOR},
+		{name: "should reject unexpected breaks", data: `9f 81 ff ff`, expect: ErrInvalidCBOR},
+		{name: "should reject reserved additional information", data: `1c`, expect: ErrInvalidCBOR},
+		{name: "should reject non-finite floats", data: `a1 19 1e7f a1 61 66 f9 7c00`, expect: ErrInvalidCBOR},
+		{name: "should ignore unknown integer keys", data: `a1 19 1e7f a1 02 00`, expect: nil},
+		{name: "should reject non-text nested member names", data: `a1 19 1e7f a1 61 6f a1 00 00`, expect: ErrInvalidCBOR},
+		{name: "should reject deeply nested items", data: strings.Repeat("81", maxCBORDepth+1) + "00", expect: ErrInvalidCBOR},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var problem RawProblem
+			err := UnmarshalCBOR(cborHex(t, test.data), &problem)
+			if test.expect == nil {
+				if err != nil {
+					t.Errorf("expected unknown integer keys to be ignored, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, test.expect) {
+				t.Errorf("expected %v, got %v", test.expect, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalCBOR_Validated(t *testing.T) {
+	var problem ValidProblem
+	err := UnmarshalCBOR(cborHex(t, `a1 21 62 6869`), &problem)
+	if !errors.Is(err, ErrTitleMustBeSet) {
+		t.Errorf("expected ErrTitleMustBeSet, got %v", err)
+	}
+}
+
+func TestWriter_CBOR(t *testing.T) {
+	problem := NewDetailedProblem(http.StatusTooManyRequests, "Slow down.")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json;q=0.5, "+ConciseProblemMediaType)
+	w := httptest.NewRecorder()
+	(&Writer{}).Write(w, r, problem)
+
+	if ct := w.Header().Get("Content-Type"); ct != ConciseProblemMediaType {
+		t.Errorf("expected content type %q, got %q", ConciseProblemMediaType, ct)
+	}
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	var decoded Problem
+	if err := UnmarshalCBOR(w.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(&decoded, problem) {
+		t.Errorf("problems were not equal: wanted\n%+v\nbut got\n%+v", problem, &decoded)
+	}
+}
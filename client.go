@@ -0,0 +1,80 @@
+package problems
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MaxResponseBodySize is the maximum number of bytes which FromResponse and
+// FromResponseExt will read from the body of a problem response.
+var MaxResponseBodySize int64 = 1 << 20
+
+// FromResponse converts an unsuccessful HTTP response into an error. If the
+// response's status code is less than 400 then nil is returned.
+//
+// If the response has a ProblemMediaType, ProblemMediaTypeXML, or
+// ConciseProblemMediaType content type then its body is decoded and returned as
+// a *Problem. Otherwise, a *Problem is created from the status code of the
+// response and its body is left unread. In either case, the caller remains
+// responsible for closing the body.
+//
+// If the problem could not be decoded, or its body is larger than
+// MaxResponseBodySize, then the error returned is not a problem.
+func FromResponse(resp *http.Response) error {
+	return decodeResponse(resp, New())
+}
+
+// FromResponseExt behaves identically to FromResponse, but decodes the problem
+// response into an *ExtendedProblem with extensions of type T.
+func FromResponseExt[T any](resp *http.Response) error {
+	return decodeResponse(resp, NewExt[T]())
+}
+
+// decodeResponse decodes the body of resp into p, and returns p.
+func decodeResponse[P interface {
+	Details
+	error
+}](resp *http.Response, p P) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	var unmarshal func([]byte, any) error
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case ProblemMediaType:
+		unmarshal = json.Unmarshal
+	case ProblemMediaTypeXML:
+		unmarshal = xml.Unmarshal
+	case ConciseProblemMediaType:
+		unmarshal = UnmarshalCBOR
+	default:
+		problem := p.details().WithStatus(resp.StatusCode)
+		if problem.Title == "" {
+			problem.Title = strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+		}
+		return p
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseBodySize+1))
+	if err != nil {
+		return fmt.Errorf("%s: reading problem response: %w", errPrefix, err)
+	}
+	if int64(len(body)) > MaxResponseBodySize {
+		return ErrResponseTooLarge
+	}
+
+	if err := unmarshal(body, p); err != nil {
+		return fmt.Errorf("%s: decoding problem response: %w", errPrefix, err)
+	}
+
+	if problem := p.details(); problem.Status == 0 {
+		problem.Status = resp.StatusCode
+	}
+	return p
+}
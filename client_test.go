@@ -0,0 +1,157 @@
+package problems
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromResponse(t *testing.T) {
+	notFound := NewDetailedProblem(http.StatusNotFound, "That thing doesn't exist.")
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		expect  *Problem
+	}{
+		{
+			name:    "should decode json problems",
+			handler: ProblemHandler(notFound),
+			expect:  notFound,
+		},
+		{
+			name:    "should decode xml problems",
+			handler: XMLProblemHandler(notFound),
+			expect:  notFound,
+		},
+		{
+			name:    "should decode concise problems",
+			handler: (&Writer{Offers: []string{ConciseProblemMediaType}}).Handler(notFound),
+			expect:  notFound,
+		},
+		{
+			name: "should synthesize problems from non-problem responses",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "oops", http.StatusServiceUnavailable)
+			},
+			expect: NewStatusProblem(http.StatusServiceUnavailable),
+		},
+		{
+			name: "should default status to the response status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", ProblemMediaType+"; charset=utf-8")
+				w.WriteHeader(http.StatusConflict)
+				_, _ = io.WriteString(w, `{"type":"https://example.com/probs/conflict","title":"Conflict"}`)
+			},
+			expect: New().WithType("https://example.com/probs/conflict").WithStatus(http.StatusConflict),
+		},
+		{
+			name: "should return nil for successful responses",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := testServer(test.handler)
+			defer server.Close()
+
+			resp, _ := getResponse("/", server)
+			defer resp.Body.Close()
+
+			err := FromResponse(resp)
+			if test.expect == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var problem *Problem
+			if !errors.As(err, &problem) {
+				t.Fatalf("expected a *Problem, got %v", err)
+			}
+
+			if !reflect.DeepEqual(problem, test.expect) {
+				t.Errorf("problems were not equal: wanted\n%#+v\n but got\n%#+v", test.expect, problem)
+			}
+		})
+	}
+}
+
+func TestFromResponseExt(t *testing.T) {
+	expect := NewExt[creditProblemExt]().
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}})
+
+	server := testServer(NegotiatedProblemHandler(expect))
+	defer server.Close()
+
+	resp, _ := getResponse("/", server)
+	defer resp.Body.Close()
+
+	var problem *ExtendedProblem[creditProblemExt]
+	if err := FromResponseExt[creditProblemExt](resp); !errors.As(err, &problem) {
+		t.Fatalf("expected an *ExtendedProblem, got %v", err)
+	}
+
+	if problem.Extensions.Balance != 30 || len(problem.Extensions.Accounts) != 1 {
+		t.Errorf("extensions were not decoded: %#+v", problem.Extensions)
+	}
+}
+
+func TestFromResponse_tooLarge(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{ProblemMediaType}},
+		Body:       io.NopCloser(strings.NewReader(`{"title":"` + strings.Repeat("a", int(MaxResponseBodySize)) + `"}`)),
+	}
+
+	if err := FromResponse(resp); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestFromResponse_invalid(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ProblemMediaType)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `not json`)
+	}
+
+	server := testServer(handler)
+	defer server.Close()
+
+	resp, _ := getResponse("/", server)
+	defer resp.Body.Close()
+
+	err := FromResponse(resp)
+
+	var problem *Problem
+	if err == nil || errors.As(err, &problem) {
+		t.Errorf("expected a decoding error, got %v", err)
+	}
+}
+
+func TestFromResponse_unknownStatus(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: 599,
+		Status:     "599 Network Connect Timeout",
+		Header:     http.Header{},
+		Body:       http.NoBody,
+	}
+
+	var problem *Problem
+	if err := FromResponse(resp); !errors.As(err, &problem) {
+		t.Fatalf("expected a *Problem, got %v", err)
+	}
+
+	if problem.Title != "Network Connect Timeout" {
+		t.Errorf("expected title to be taken from the status line, got %q", problem.Title)
+	}
+}
@@ -0,0 +1,223 @@
+package problems
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// An IDSource extracts a correlation ID from a request. It returns an empty
+// string if the request does not carry an ID.
+type IDSource func(r *http.Request) string
+
+// maxHeaderIDLength is the maximum length of a correlation ID taken from a
+// request header by HeaderID.
+const maxHeaderIDLength = 128
+
+// HeaderID returns an IDSource which uses the value of the named request
+// header, such as "X-Request-ID", as the correlation ID.
+//
+// Since the value is supplied by the client, and is included in both problems
+// and response headers, it is only used if it is no longer than 128 characters
+// and consists solely of letters, digits, and the characters "-", ".", "_" and
+// "~", which can be used in a URI without being escaped. Otherwise, the request
+// is treated as if it did not carry an ID.
+func HeaderID(name string) IDSource {
+	return func(r *http.Request) string {
+		id := strings.TrimSpace(r.Header.Get(name))
+		if !isValidHeaderID(id) {
+			return ""
+		}
+		return id
+	}
+}
+
+// isValidHeaderID reports whether id may be used as a correlation ID by
+// HeaderID.
+func isValidHeaderID(id string) bool {
+	if len(id) > maxHeaderIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '.', c == '_', c == '~':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// TraceParentID is an IDSource which uses the trace ID of the W3C Trace Context
+// traceparent header as the correlation ID.
+func TraceParentID(r *http.Request) string {
+	parts := strings.Split(strings.TrimSpace(r.Header.Get("traceparent")), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 {
+		return ""
+	}
+
+	traceID := strings.ToLower(parts[1])
+	if _, err := hex.DecodeString(traceID); err != nil || traceID == strings.Repeat("0", 32) {
+		return ""
+	}
+	return traceID
+}
+
+// NewUUID returns a new, randomly generated, version 4 UUID. It can be used as
+// the Generate function of a Correlation.
+func NewUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// A Correlation links the problems written by a Writer to the request which
+// caused them, so that a problem reported by a client can be found in a
+// service's logs.
+//
+// The correlation ID of a request is taken from the first of the Sources to
+// return an ID, or is generated if none of them do.
+type Correlation struct {
+	// Sources are consulted, in order, for the correlation ID of a request.
+	Sources []IDSource
+
+	// Generate, if set, generates a correlation ID for requests which do not
+	// carry one, such as NewUUID.
+	Generate func() string
+
+	// InstanceFormat, if set, is a format string such as "urn:uuid:%s" or
+	// "/errors/%s", which is formatted with the correlation ID and used as
+	// the instance of problems which do not already have one.
+	InstanceFormat string
+
+	// Extension, if set, is the name of an extension member, such as
+	// "trace_id", which is added to problems with the correlation ID as its
+	// value. Extension members are not added to HTML responses.
+	Extension string
+
+	// Header, if set, is the name of a response header, such as
+	// "X-Request-ID", which is set to the correlation ID.
+	Header string
+}
+
+// ID returns the correlation ID of the provided request.
+func (c *Correlation) ID(r *http.Request) string {
+	for _, source := range c.Sources {
+		if id := source(r); id != "" {
+			return id
+		}
+	}
+
+	if c.Generate != nil {
+		return c.Generate()
+	}
+	return ""
+}
+
+// apply sets the response header, and returns a copy of p containing the
+// provided correlation ID.
+func (c *Correlation) apply(w http.ResponseWriter, id string, p Details) Details {
+	if id == "" {
+		return p
+	}
+
+	if c.Header != "" {
+		w.Header().Set(c.Header, id)
+	}
+
+	if c.InstanceFormat != "" && p.details().Instance == "" {
+		instance := fmt.Sprintf(c.InstanceFormat, id)
+		p = modify(p, func(problem *Problem) {
+			problem.Instance = instance
+		})
+	}
+
+	if c.Extension != "" {
+		p = &withMember{Details: p, name: c.Extension, value: id}
+	}
+	return p
+}
+
+// withMember adds a single extension member to a problem when it is encoded,
+// unless the problem already contains a member with the same name.
+type withMember struct {
+	Details
+	name  string
+	value string
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p *withMember) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(p.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(base, &members); err != nil {
+		return nil, err
+	}
+	if _, ok := members[p.name]; ok {
+		return base, nil
+	}
+
+	ext, err := json.Marshal(map[string]string{p.name: p.value})
+	if err != nil {
+		return nil, err
+	}
+	return mergeMembers(base, ext)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (p *withMember) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	var buf bytes.Buffer
+	if err := encodeXML(&buf, p.Details); err != nil {
+		return err
+	}
+
+	d := xml.NewDecoder(&buf)
+	depth, exists := 0, false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				t = xmlRoot
+			} else {
+				exists = exists || (depth == 2 && t.Name.Local == p.name)
+				t = xml.StartElement{Name: xml.Name{Local: t.Name.Local}}
+			}
+			tok = t
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				if !exists {
+					if err := e.EncodeElement(p.value, xml.StartElement{Name: xml.Name{Local: p.name}}); err != nil {
+						return err
+					}
+				}
+				return e.EncodeToken(xmlRoot.End())
+			}
+			tok = xml.EndElement{Name: xml.Name{Local: t.Name.Local}}
+		case xml.ProcInst, xml.Directive:
+			continue
+		}
+
+		if err := e.EncodeToken(xml.CopyToken(tok)); err != nil {
+			return err
+		}
+	}
+}
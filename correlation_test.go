@@ -0,0 +1,172 @@
+package problems
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHeaderID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		expect string
+	}{
+		{name: "should use valid ids", header: "abc-123_DEF.4~5", expect: "abc-123_DEF.4~5"},
+		{name: "should trim whitespace", header: " abc123 ", expect: "abc123"},
+		{name: "should accept ids of the maximum length", header: strings.Repeat("a", 128), expect: strings.Repeat("a", 128)},
+		{name: "should reject ids which are too long", header: strings.Repeat("a", 129)},
+		{name: "should reject ids containing spaces", header: "abc 123"},
+		{name: "should reject ids containing uri delimiters", header: "../../admin?x=1#y"},
+		{name: "should reject ids containing markup", header: "<script>"},
+		{name: "should reject ids containing non-ascii characters", header: "abcé"},
+		{name: "should ignore missing headers"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Request-ID", test.header)
+
+			if got := HeaderID("X-Request-ID")(req); got != test.expect {
+				t.Errorf("expected id %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestTraceParentID(t *testing.T) {
+	tests := []struct {
+		header string
+		expect string
+	}{
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expect: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", expect: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{header: "00-not-hex-01"},
+		{header: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("traceparent", test.header)
+
+			if got := TraceParentID(req); got != test.expect {
+				t.Errorf("expected trace id %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	uuid := NewUUID()
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid) {
+		t.Errorf("expected a version 4 uuid, got %q", uuid)
+	}
+
+	if uuid == NewUUID() {
+		t.Error("expected uuids to be unique")
+	}
+}
+
+func TestWriter_correlation(t *testing.T) {
+	writer := &Writer{
+		Correlation: &Correlation{
+			Sources:        []IDSource{HeaderID("X-Request-ID"), TraceParentID},
+			Generate:       func() string { return "generated" },
+			InstanceFormat: "/errors/%s",
+			Extension:      "trace_id",
+			Header:         "X-Request-ID",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		problem        Details
+		expectID       string
+		expectInstance string
+	}{
+		{
+			name:           "should use the request id header",
+			headers:        map[string]string{"X-Request-ID": "abc123"},
+			problem:        NewStatusProblem(http.StatusNotFound),
+			expectID:       "abc123",
+			expectInstance: "/errors/abc123",
+		},
+		{
+			name:           "should use the traceparent header",
+			headers:        map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			problem:        NewStatusProblem(http.StatusNotFound),
+			expectID:       "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectInstance: "/errors/4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:           "should fall back to the next source for invalid ids",
+			headers:        map[string]string{"X-Request-ID": "not a valid id", "traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			problem:        NewStatusProblem(http.StatusNotFound),
+			expectID:       "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectInstance: "/errors/4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:           "should generate an id",
+			problem:        NewExt[creditProblemExt]().WithStatus(http.StatusForbidden),
+			expectID:       "generated",
+			expectInstance: "/errors/generated",
+		},
+		{
+			name:           "should not replace existing instances",
+			problem:        NewStatusProblem(http.StatusNotFound).WithInstance("/users/1"),
+			expectID:       "generated",
+			expectInstance: "/users/1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, accept := range []string{ProblemMediaType, ProblemMediaTypeXML} {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Accept", accept)
+				for k, v := range test.headers {
+					req.Header.Set(k, v)
+				}
+				rec := httptest.NewRecorder()
+
+				writer.Write(rec, req, test.problem)
+
+				if id := rec.Header().Get("X-Request-ID"); id != test.expectID {
+					t.Errorf("expected X-Request-ID header %q, got %q", test.expectID, id)
+				}
+
+				var response RawProblem
+				var err error
+				if accept == ProblemMediaType {
+					err = json.Unmarshal(rec.Body.Bytes(), &response)
+				} else {
+					err = xml.Unmarshal(rec.Body.Bytes(), &response)
+				}
+				if err != nil {
+					t.Fatalf("failed to decode %s response %q: %s", accept, rec.Body.String(), err)
+				}
+
+				if response.Instance != test.expectInstance {
+					t.Errorf("expected %s instance %q, got %q", accept, test.expectInstance, response.Instance)
+				}
+
+				var traceID string
+				if err := json.Unmarshal(response.Extensions["trace_id"], &traceID); err != nil || traceID != test.expectID {
+					t.Errorf("expected %s trace_id %q, got %s", accept, test.expectID, response.Extensions["trace_id"])
+				}
+			}
+
+			if test.problem.details().Instance != "" && test.problem.details().Instance != "/users/1" {
+				t.Errorf("the original problem was modified")
+			}
+		})
+	}
+}
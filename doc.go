@@ -17,5 +17,6 @@
 // Additionally, this library also ships with default http.HandlerFunc
 // implementations which are capable of writing problems to a
 // http.ResponseWriter in either of the two standard media formats, JSON and
-// XML.
+// XML, as well as a Writer which selects between them based on the Accept
+// header of the request being served.
 package problems
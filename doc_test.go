@@ -68,13 +68,11 @@ func ExampleExtendedProblem() {
 	//   "title": "Forbidden",
 	//   "status": 403,
 	//   "detail": "You do not have sufficient funds to complete this transaction.",
-	//   "extensions": {
-	//     "balance": 30,
-	//     "accounts": [
-	//       "/account/12345",
-	//       "/account/67890"
-	//     ]
-	//   }
+	//   "balance": 30,
+	//   "accounts": [
+	//     "/account/12345",
+	//     "/account/67890"
+	//   ]
 	// }
 }
 
@@ -107,3 +105,12 @@ func ExampleExtendedProblem_embedding() {
 	//   ]
 	// }
 }
+
+func ExampleParseRaw() {
+	upstream := `{"type":"https://example.com/out-of-credit","title":"You do not have enough credit.","status":403,"balance":30}`
+	problem, _ := problems.ParseRaw([]byte(upstream))
+	problem.WithInstance("/account/12345/msgs/abc")
+	b, _ := json.Marshal(problem)
+	fmt.Println(string(b))
+	// Output: {"type":"https://example.com/out-of-credit","title":"You do not have enough credit.","status":403,"instance":"/account/12345/msgs/abc","balance":30}
+}
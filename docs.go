@@ -0,0 +1,184 @@
+package problems
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// docsIndexTemplate renders the list of every problem type in a Registry.
+var docsIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Problem Types</title>
+</head>
+<body>
+<h1>Problem Types</h1>
+<ul>
+{{- range .}}
+<li><a href="{{.Path}}">{{.Title}}</a>{{if .Status}} ({{.Status}}){{end}}</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// docsTypeTemplate renders the documentation of a single problem type.
+var docsTypeTemplate = template.Must(template.New("type").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Type: <code>{{.URI}}</code></p>
+{{- if .Status}}
+<p>Status: {{.Status}}</p>
+{{- end}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .Extensions}}
+<h2>Extension Members</h2>
+<table>
+<tr><th>Name</th><th>Type</th><th>Description</th></tr>
+{{- range .Extensions}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Example}}
+<h2>Example</h2>
+<pre>{{.Example}}</pre>
+{{- end}}
+</body>
+</html>
+`))
+
+// docsOffers are the media types which documentation pages are served as.
+var docsOffers = []string{HTMLMediaType, JSONMediaType}
+
+// A docsPage is the data used to render the documentation of a problem type.
+type docsPage struct {
+	ProblemType
+	Path    string
+	Example string
+}
+
+// Handler returns a http.Handler which serves documentation for every problem
+// type in the registry beneath the provided base path, so that the type URIs
+// of registered problems resolve to a human-readable description.
+//
+// The documentation for each problem type is served at the path of its type
+// URI, if that path is beneath the base path, and at the base path joined with
+// its name. An index of every problem type is served at the base path itself.
+// Pages are served as HTML, or as JSON for clients which prefer JSONMediaType.
+func (r *Registry) Handler(basePath string) http.Handler {
+	basePath = "/" + strings.Trim(basePath, "/")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			_ = DefaultWriter.Write(w, req, MethodNotAllowed(http.MethodGet, http.MethodHead))
+			return
+		}
+
+		mediaType, ok := negotiate(req.Header.Values("Accept"), docsOffers)
+		if !ok {
+			mediaType = HTMLMediaType
+		}
+		w.Header().Add("Vary", "Accept")
+
+		reqPath := "/" + strings.Trim(req.URL.Path, "/")
+		if reqPath == basePath {
+			r.serveIndex(w, mediaType, basePath)
+			return
+		}
+
+		t, ok := r.lookupPath(basePath, reqPath)
+		if !ok {
+			_ = DefaultWriter.Write(w, req, NewStatusProblem(http.StatusNotFound))
+			return
+		}
+		serveType(w, mediaType, docsPage{ProblemType: t, Path: reqPath})
+	})
+}
+
+// lookupPath returns the problem type documented at the provided path, which
+// must already be unescaped.
+func (r *Registry) lookupPath(basePath, reqPath string) (ProblemType, bool) {
+	for _, t := range r.Types() {
+		if uriPath(basePath, t) == reqPath || namePath(basePath, t) == reqPath {
+			return t, true
+		}
+	}
+	return ProblemType{}, false
+}
+
+// serveIndex writes the index of every problem type in the registry.
+func (r *Registry) serveIndex(w http.ResponseWriter, mediaType, basePath string) {
+	types := r.Types()
+	if mediaType == JSONMediaType {
+		w.Header().Set("Content-Type", JSONMediaType)
+		if types == nil {
+			types = []ProblemType{}
+		}
+		_ = json.NewEncoder(w).Encode(types)
+		return
+	}
+
+	pages := make([]docsPage, 0, len(types))
+	for _, t := range types {
+		page := docsPage{ProblemType: t, Path: uriPath(basePath, t)}
+		if page.Path == "" {
+			page.Path = namePath(basePath, t)
+		}
+		if page.Path == "" {
+			page.Path = t.URI
+		} else {
+			page.Path = (&url.URL{Path: page.Path}).EscapedPath()
+		}
+		pages = append(pages, page)
+	}
+	w.Header().Set("Content-Type", HTMLMediaType+"; charset=utf-8")
+	_ = docsIndexTemplate.Execute(w, pages)
+}
+
+// serveType writes the documentation of a single problem type.
+func serveType(w http.ResponseWriter, mediaType string, page docsPage) {
+	if mediaType == JSONMediaType {
+		w.Header().Set("Content-Type", JSONMediaType)
+		_ = json.NewEncoder(w).Encode(page.ProblemType)
+		return
+	}
+
+	if page.ProblemType.Example != nil {
+		example, err := json.MarshalIndent(page.ProblemType.Example, "", "  ")
+		if err == nil {
+			page.Example = string(example)
+		}
+	}
+	w.Header().Set("Content-Type", HTMLMediaType+"; charset=utf-8")
+	_ = docsTypeTemplate.Execute(w, page)
+}
+
+// uriPath returns the path of the type URI of t, if it is beneath basePath.
+func uriPath(basePath string, t ProblemType) string {
+	u, err := url.Parse(t.URI)
+	if err != nil || !strings.HasPrefix(u.Path, strings.TrimSuffix(basePath, "/")+"/") {
+		return ""
+	}
+	return "/" + strings.Trim(u.Path, "/")
+}
+
+// namePath returns the unescaped path beneath basePath named after t, if it has
+// a name.
+func namePath(basePath string, t ProblemType) string {
+	if t.Name == "" {
+		return ""
+	}
+	return strings.TrimSuffix(basePath, "/") + "/" + t.Name
+}
@@ -0,0 +1,146 @@
+package problems
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Handler(t *testing.T) {
+	example := outOfCredit
+	example.Example = Extend(outOfCredit.New(), creditProblemExt{Balance: 30})
+
+	registry, err := NewRegistry(example, ProblemType{
+		URI:   "https://example.com/probs/rate-limited",
+		Title: "Too many requests.",
+	}, ProblemType{
+		Name:  "account frozen",
+		URI:   "https://example.com/problems/account-frozen",
+		Title: "Your account is frozen.",
+	})
+	if err != nil {
+		t.Fatalf("failed to create registry: %s", err)
+	}
+	handler := registry.Handler("/probs/")
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		accept        string
+		expectStatus  int
+		expectType    string
+		expectAllow   string
+		expectContent []string
+	}{
+		{
+			name:          "should serve an html index",
+			path:          "/probs/",
+			expectStatus:  http.StatusOK,
+			expectType:    HTMLMediaType + "; charset=utf-8",
+			expectContent: []string{`<a href="/probs/out-of-credit">You do not have enough credit.</a> (403)`, `<a href="/probs/rate-limited">`, `<a href="/probs/account%20frozen">`},
+		},
+		{
+			name:          "should serve a json index",
+			path:          "/probs",
+			accept:        JSONMediaType,
+			expectStatus:  http.StatusOK,
+			expectType:    JSONMediaType,
+			expectContent: []string{`"name":"out-of-credit"`, `"type":"https://example.com/probs/rate-limited"`},
+		},
+		{
+			name:         "should serve html documentation for a type",
+			path:         "/probs/out-of-credit",
+			accept:       "text/html,*/*;q=0.8",
+			expectStatus: http.StatusOK,
+			expectType:   HTMLMediaType + "; charset=utf-8",
+			expectContent: []string{
+				`<h1>You do not have enough credit.</h1>`,
+				`<code>https://example.com/probs/out-of-credit</code>`,
+				`<tr><td><code>balance</code></td><td>number</td><td>The current balance of the account.</td></tr>`,
+				`&#34;balance&#34;: 30`,
+			},
+		},
+		{
+			name:          "should serve json documentation for a type",
+			path:          "/probs/rate-limited",
+			accept:        JSONMediaType,
+			expectStatus:  http.StatusOK,
+			expectType:    JSONMediaType,
+			expectContent: []string{`"title":"Too many requests."`},
+		},
+		{
+			name:          "should serve documentation for names which must be escaped",
+			path:          "/probs/account%20frozen",
+			accept:        JSONMediaType,
+			expectStatus:  http.StatusOK,
+			expectType:    JSONMediaType,
+			expectContent: []string{`"title":"Your account is frozen."`},
+		},
+		{
+			name:          "should serve not found problems for unknown types",
+			path:          "/probs/unknown",
+			expectStatus:  http.StatusNotFound,
+			expectType:    ProblemMediaType,
+			expectContent: []string{`"title":"Not Found"`},
+		},
+		{
+			name:          "should reject unsupported methods",
+			method:        http.MethodPost,
+			path:          "/probs/out-of-credit",
+			expectStatus:  http.StatusMethodNotAllowed,
+			expectType:    ProblemMediaType,
+			expectAllow:   "GET, HEAD",
+			expectContent: []string{`"title":"Method Not Allowed"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, test.path, nil)
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.expectStatus {
+				t.Errorf("Expected HTTP status code to be %d, got %d", test.expectStatus, rec.Code)
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != test.expectType {
+				t.Errorf("Expected Content-Type to be %q, got %q", test.expectType, ct)
+			}
+
+			if allow := rec.Header().Get("Allow"); allow != test.expectAllow {
+				t.Errorf("Expected Allow to be %q, got %q", test.expectAllow, allow)
+			}
+
+			for _, content := range test.expectContent {
+				if !strings.Contains(rec.Body.String(), content) {
+					t.Errorf("Expected body to contain %q, got %q", content, rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_Handler_emptyIndex(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", JSONMediaType)
+	rec := httptest.NewRecorder()
+
+	(&Registry{}).Handler("/").ServeHTTP(rec, req)
+
+	var types []ProblemType
+	if err := json.NewDecoder(rec.Body).Decode(&types); err != nil || types == nil {
+		t.Errorf("expected an empty list of problem types, got %v (%v)", types, err)
+	}
+}
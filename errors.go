@@ -1,6 +1,9 @@
 package problems
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const errPrefix = "problems"
 
@@ -8,6 +11,23 @@ const errPrefix = "problems"
 // the problem is validated without a title.
 var ErrTitleMustBeSet = fmt.Errorf("%s: problem title must be set", errPrefix)
 
+// ErrExtensionsMustBeObject is the error returned when serializing an
+// ExtendedProblem whose extensions do not serialize to a JSON object.
+var ErrExtensionsMustBeObject = fmt.Errorf("%s: problem extensions must serialize to an object", errPrefix)
+
+// ErrResponseTooLarge is the error returned from a call to FromResponse if the
+// body of the problem response exceeds MaxResponseBodySize.
+var ErrResponseTooLarge = fmt.Errorf("%s: problem response body is too large", errPrefix)
+
+// ErrInvalidCBOR is the error returned from a call to UnmarshalCBOR if the
+// data is not a well-formed Concise Problem Details data item.
+var ErrInvalidCBOR = fmt.Errorf("%s: invalid concise problem details", errPrefix)
+
+// ErrResponseStarted is wrapped by the error passed to the OnError function of
+// a Writer when a handler returns an error after it has started writing its
+// response, so that no problem can be written.
+var ErrResponseStarted = fmt.Errorf("%s: response already started", errPrefix)
+
 // ErrInvalidProblemType is the error type returned if a problems type is not a
 // valid URI when it is validated. The inner Err will contain the error
 // returned from attempting to parse the invalid URI.
@@ -28,3 +48,142 @@ func NewErrInvalidProblemType(value string, e error) error {
 func (e *ErrInvalidProblemType) Error() string {
 	return fmt.Sprintf("%s: problem type must be a valid uri: %s", errPrefix, e.Err)
 }
+
+// Unwrap returns the error returned from attempting to parse the invalid URI.
+func (e *ErrInvalidProblemType) Unwrap() error {
+	return e.Err
+}
+
+// ErrReservedMember is the error type returned when an ExtendedProblem's
+// extensions define a member whose name collides with one of the standard
+// problem details members, such as "type" or "status".
+type ErrReservedMember struct {
+	Member string
+}
+
+// NewErrReservedMember returns a new ErrReservedMember instance for the
+// provided member name.
+func NewErrReservedMember(member string) error {
+	return &ErrReservedMember{Member: member}
+}
+
+func (e *ErrReservedMember) Error() string {
+	return fmt.Sprintf("%s: extension member %q collides with a standard problem member", errPrefix, e.Member)
+}
+
+// ErrInvalidXMLName is the error type returned when serializing an
+// ExtendedProblem as XML whose extensions contain a member, at any depth, whose
+// name cannot be used as the name of an XML element, such as "a b" or "1x".
+type ErrInvalidXMLName struct {
+	Member string
+}
+
+// NewErrInvalidXMLName returns a new ErrInvalidXMLName instance for the
+// provided member name.
+func NewErrInvalidXMLName(member string) error {
+	return &ErrInvalidXMLName{Member: member}
+}
+
+func (e *ErrInvalidXMLName) Error() string {
+	return fmt.Sprintf("%s: extension member %q is not a valid xml element name", errPrefix, e.Member)
+}
+
+// ErrDuplicateProblemType is the error type returned when registering a
+// problem type whose name or URI has already been registered with a Registry.
+type ErrDuplicateProblemType struct {
+	Value string
+}
+
+// NewErrDuplicateProblemType returns a new ErrDuplicateProblemType instance
+// for the provided name or URI.
+func NewErrDuplicateProblemType(value string) error {
+	return &ErrDuplicateProblemType{Value: value}
+}
+
+func (e *ErrDuplicateProblemType) Error() string {
+	return fmt.Sprintf("%s: problem type %q is already registered", errPrefix, e.Value)
+}
+
+// ErrInvalidProblem is the error type returned from a call to ValidateWith if
+// the problem violates any of the Validator's requirements. Each of the
+// violations found is contained in Violations, and can be matched using
+// errors.Is and errors.As.
+type ErrInvalidProblem struct {
+	Violations []error
+}
+
+func (e *ErrInvalidProblem) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, strings.TrimPrefix(v.Error(), errPrefix+": "))
+	}
+	return fmt.Sprintf("%s: invalid problem: %s", errPrefix, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the violations contained in the error.
+func (e *ErrInvalidProblem) Unwrap() []error {
+	return e.Violations
+}
+
+// ErrInvalidStatus is the error type returned when validating a problem whose
+// status is not a valid HTTP status code.
+type ErrInvalidStatus struct {
+	Status int
+}
+
+// NewErrInvalidStatus returns a new ErrInvalidStatus instance for the provided
+// status code.
+func NewErrInvalidStatus(status int) error {
+	return &ErrInvalidStatus{Status: status}
+}
+
+func (e *ErrInvalidStatus) Error() string {
+	return fmt.Sprintf("%s: problem status must be between 100 and 599: %d", errPrefix, e.Status)
+}
+
+// ErrInvalidInstance is the error type returned when validating a problem whose
+// instance is not a valid URI reference. The inner Err explains why the URI
+// reference is invalid.
+type ErrInvalidInstance struct {
+	Err   error
+	Value string
+}
+
+// NewErrInvalidInstance returns a new ErrInvalidInstance instance which wraps
+// the provided error.
+func NewErrInvalidInstance(value string, e error) error {
+	return &ErrInvalidInstance{
+		Err:   e,
+		Value: value,
+	}
+}
+
+func (e *ErrInvalidInstance) Error() string {
+	return fmt.Sprintf("%s: problem instance must be a valid uri reference: %s", errPrefix, e.Err)
+}
+
+// Unwrap returns the error explaining why the URI reference is invalid.
+func (e *ErrInvalidInstance) Unwrap() error {
+	return e.Err
+}
+
+// ErrNonStandardTitle is the error type returned when validating an
+// "about:blank" problem whose title is not the standard reason phrase of its
+// status code.
+type ErrNonStandardTitle struct {
+	Title  string
+	Expect string
+}
+
+// NewErrNonStandardTitle returns a new ErrNonStandardTitle instance for the
+// provided title and the reason phrase that was expected instead.
+func NewErrNonStandardTitle(title, expect string) error {
+	return &ErrNonStandardTitle{
+		Title:  title,
+		Expect: expect,
+	}
+}
+
+func (e *ErrNonStandardTitle) Error() string {
+	return fmt.Sprintf("%s: about:blank problem title must be %q: got %q", errPrefix, e.Expect, e.Title)
+}
@@ -1,20 +1,38 @@
 package problems
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 )
 
+// reservedMembers contains the names of the standard problem details members
+// defined by RFC-9457. Extension members may not reuse any of these names.
+var reservedMembers = map[string]struct{}{
+	"type":     {},
+	"title":    {},
+	"status":   {},
+	"detail":   {},
+	"instance": {},
+}
+
 // An ExtendedProblem extends the Problem type with a new field, Extensions,
 // of type T.
+//
+// As described in section 3.2 of RFC-9457, the members of Extensions are
+// serialized alongside the standard problem details members rather than being
+// nested beneath a separate key. As such, T must serialize to a JSON object
+// (typically a struct or a map) and must not define any members which collide
+// with the standard members.
 type ExtendedProblem[T any] struct {
 	Problem
 
 	// Extensions allows for Problem type definitions to extend the standard
 	// problem details object with additional members that are specific to that
 	// problem type.
-	Extensions T `json:"extensions,omitempty" xml:"extensions,omitempty"`
+	Extensions T `json:"-" xml:"-"`
 }
 
 // NewExt returns a new ExtendedProblem with all the same default values
@@ -26,7 +44,8 @@ func NewExt[T any]() *ExtendedProblem[T] {
 }
 
 // ExtFromError returns a new ExtendedProblem instance which contains the
-// string version of the provided error as the details of the problem.
+// string version of the provided error as the details of the problem. The
+// error is retained as the cause of the problem.
 func ExtFromError[T any](err error) *ExtendedProblem[T] {
 	return NewExt[T]().WithError(err)
 }
@@ -77,9 +96,18 @@ func (p *ExtendedProblem[T]) WithDetailf(format string, args ...interface{}) *Ex
 	return p
 }
 
-// WithError sets the detail message to the provided error.
+// WithError sets the detail message to the provided error, and retains the
+// error as the cause of the problem.
 func (p *ExtendedProblem[T]) WithError(err error) *ExtendedProblem[T] {
-	p.Detail = err.Error()
+	p.Problem.WithError(err)
+	return p
+}
+
+// WithCause sets the underlying error which caused the problem, without
+// changing its detail message. The cause is returned by Unwrap, but is never
+// serialized.
+func (p *ExtendedProblem[T]) WithCause(err error) *ExtendedProblem[T] {
+	p.Problem.WithCause(err)
 	return p
 }
 
@@ -89,15 +117,164 @@ func (p *ExtendedProblem[T]) WithInstance(instance string) *ExtendedProblem[T] {
 	return p
 }
 
+// WithHeader sets the named header field to the provided values, replacing
+// any existing values. The header fields of a problem are written along with
+// it by each of the HTTP handlers, but are never serialized.
+func (p *ExtendedProblem[T]) WithHeader(name string, values ...string) *ExtendedProblem[T] {
+	p.Problem.WithHeader(name, values...)
+	return p
+}
+
+// WithRetryAfter sets the Retry-After header field to the provided delay, in
+// whole seconds rounded up. If the delay is not positive then the header field
+// is removed.
+func (p *ExtendedProblem[T]) WithRetryAfter(d time.Duration) *ExtendedProblem[T] {
+	p.Problem.WithRetryAfter(d)
+	return p
+}
+
 // WithExtension sets the extensions value to the provided extension of type T.
 func (p *ExtendedProblem[T]) WithExtension(ext T) *ExtendedProblem[T] {
 	p.Extensions = ext
 	return p
 }
 
+// Clone returns a deep copy of the ExtendedProblem, including its extensions,
+// which can be modified without affecting the original.
+//
+// Pointers, maps, slices, and interfaces within the extensions are copied
+// recursively, with the exception of unexported struct fields which are
+// copied as-is. Extensions containing reference cycles are not supported.
+func (p *ExtendedProblem[T]) Clone() *ExtendedProblem[T] {
+	return &ExtendedProblem[T]{
+		Problem:    *p.Problem.Clone(),
+		Extensions: deepCopy(p.Extensions),
+	}
+}
+
 // Error implements the error interface and allows a Problem to be used as a
-// native error.
+// native error. The message has the same form as Problem.Error, followed by
+// the serialized extensions if there are any.
 func (p *ExtendedProblem[T]) Error() string {
-	ext, _ := json.Marshal(p.Extensions)
-	return fmt.Sprintf("%s (%d) - %s - %s", p.Title, p.Status, p.Detail, string(ext))
+	msg := p.summary()
+	ext, err := json.Marshal(p.Extensions)
+	if n, _ := extensionMembers(ext); err != nil || n == 0 {
+		return msg
+	}
+	if msg == "" {
+		return string(ext)
+	}
+	return msg + " - " + string(ext)
+}
+
+// MarshalJSON implements the json.Marshaler interface and serializes the
+// members of Extensions as top-level members of the problem details object.
+//
+// An error is returned if Extensions does not serialize to a JSON object, or
+// if it defines a member which collides with one of the standard members.
+//
+// MarshalJSON has a value receiver so that the extensions are also serialized
+// when an ExtendedProblem is marshalled by value, such as within a map.
+func (p ExtendedProblem[T]) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(p.Problem)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeMembers(base, ext)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The standard
+// problem details members are decoded into the embedded Problem, while all
+// remaining members are decoded into Extensions.
+func (p *ExtendedProblem[T]) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &p.Problem); err != nil {
+		return err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for name := range reservedMembers {
+		delete(members, name)
+	}
+
+	ext, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(ext, &p.Extensions)
+}
+
+// mergeMembers merges the members of the ext JSON object into the base JSON
+// object, preserving the order in which the members of each were serialized.
+func mergeMembers(base, ext []byte) ([]byte, error) {
+	n, err := extensionMembers(ext)
+	if err != nil || n == 0 {
+		return base, err
+	}
+
+	ext = bytes.TrimSpace(ext)
+	var buf bytes.Buffer
+	buf.Grow(len(base) + len(ext))
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(base), []byte("}")))
+	buf.WriteByte(',')
+	buf.Write(bytes.TrimPrefix(ext, []byte("{")))
+	return buf.Bytes(), nil
+}
+
+// extensionMembers verifies that the serialized extensions in ext are either
+// null or a JSON object with no members which collide with the standard
+// problem details members, and returns the number of members it contains.
+func extensionMembers(ext []byte) (int, error) {
+	if bytes.Equal(bytes.TrimSpace(ext), []byte("null")) {
+		return 0, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &members); err != nil {
+		return 0, ErrExtensionsMustBeObject
+	}
+	for name := range members {
+		if _, ok := reservedMembers[name]; ok {
+			return 0, NewErrReservedMember(name)
+		}
+	}
+	return len(members), nil
+}
+
+// A jsonMember is a single member of a serialized JSON object.
+type jsonMember struct {
+	name  string
+	value json.RawMessage
+}
+
+// jsonMembers returns the members of the provided JSON object, in the order in
+// which they appear. Nil is returned if data is not a JSON object.
+func jsonMembers(data []byte) []jsonMember {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	var members []jsonMember
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return members
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return members
+		}
+		members = append(members, jsonMember{name: key.(string), value: value})
+	}
+	return members
 }
@@ -38,7 +38,7 @@ func TestExtend(t *testing.T) {
 					Accounts: []string{"/account/12345", "/account/67890"},
 				},
 			),
-			expectJson: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"you are unauthorized to access this resource","extensions":{"balance":30,"accounts":["/account/12345","/account/67890"]}}`,
+			expectJson: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"you are unauthorized to access this resource","balance":30,"accounts":["/account/12345","/account/67890"]}`,
 		},
 		{
 			name: "should render properly with Extend",
@@ -49,7 +49,7 @@ func TestExtend(t *testing.T) {
 					Balance:  30,
 					Accounts: []string{"/account/12345", "/account/67890"},
 				}),
-			expectJson: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"account 12345 has insufficient funds","extensions":{"balance":30,"accounts":["/account/12345","/account/67890"]}}`,
+			expectJson: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"account 12345 has insufficient funds","balance":30,"accounts":["/account/12345","/account/67890"]}`,
 		},
 		{
 			name: "should render properly with Error",
@@ -59,7 +59,7 @@ func TestExtend(t *testing.T) {
 					Balance:  30,
 					Accounts: []string{"/account/12345", "/account/67890"},
 				}),
-			expectJson: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"an error occurred","extensions":{"balance":30,"accounts":["/account/12345","/account/67890"]}}`,
+			expectJson: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"an error occurred","balance":30,"accounts":["/account/12345","/account/67890"]}`,
 		},
 	}
 
@@ -122,3 +122,133 @@ func TestExtension(t *testing.T) {
 		t.Errorf("extended problem is not valid but should be: %s", err)
 	}
 }
+
+func TestExtendedProblem_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		problem    json.Marshaler
+		expectJson string
+		expectErr  bool
+	}{
+		{
+			name: "should flatten map extensions",
+			problem: NewExt[map[string]int]().
+				WithStatus(http.StatusTooManyRequests).
+				WithExtension(map[string]int{"limit": 10}),
+			expectJson: `{"type":"about:blank","title":"Too Many Requests","status":429,"limit":10}`,
+		},
+		{
+			name:       "should omit nil extensions",
+			problem:    NewExt[*creditProblemExt]().WithStatus(http.StatusNotFound),
+			expectJson: `{"type":"about:blank","title":"Not Found","status":404}`,
+		},
+		{
+			name: "should omit empty extensions",
+			problem: NewExt[map[string]int]().
+				WithStatus(http.StatusNotFound).
+				WithExtension(map[string]int{}),
+			expectJson: `{"type":"about:blank","title":"Not Found","status":404}`,
+		},
+		{
+			name: "should reject extensions which collide with standard members",
+			problem: NewExt[map[string]int]().
+				WithStatus(http.StatusNotFound).
+				WithExtension(map[string]int{"status": 500}),
+			expectErr: true,
+		},
+		{
+			name: "should reject extensions which are not objects",
+			problem: NewExt[[]string]().
+				WithStatus(http.StatusNotFound).
+				WithExtension([]string{"a"}),
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.problem)
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("expected an error but marshalled %s", string(data))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to marshal extended problem as json: %s", err)
+			}
+
+			if string(data) != test.expectJson {
+				t.Errorf("extended problem does not match expectation:\ngot\n%s\nwant\n%s", string(data), test.expectJson)
+			}
+		})
+	}
+}
+
+func TestExtendedProblem_MarshalJSON_value(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30})
+	expect := `{"type":"about:blank","title":"Forbidden","status":403,"balance":30,"accounts":null}`
+
+	data, err := json.Marshal(*problem)
+	if err != nil {
+		t.Fatalf("failed to marshal extended problem value as json: %s", err)
+	}
+	if string(data) != expect {
+		t.Errorf("extended problem value does not match expectation:\ngot\n%s\nwant\n%s", string(data), expect)
+	}
+
+	data, err = json.Marshal(map[string]ExtendedProblem[creditProblemExt]{"a": *problem})
+	if err != nil {
+		t.Fatalf("failed to marshal map of extended problems as json: %s", err)
+	}
+	if expect := `{"a":` + expect + `}`; string(data) != expect {
+		t.Errorf("map of extended problems does not match expectation:\ngot\n%s\nwant\n%s", string(data), expect)
+	}
+}
+
+func TestExtendedProblem_MarshalJSON_reservedMember(t *testing.T) {
+	problem := NewExt[map[string]string]().WithExtension(map[string]string{"title": "oops"})
+
+	_, err := json.Marshal(problem)
+
+	var reserved *ErrReservedMember
+	if !errors.As(err, &reserved) {
+		t.Fatalf("expected ErrReservedMember, got %v", err)
+	}
+
+	if reserved.Member != "title" {
+		t.Errorf("expected reserved member to be %q, got %q", "title", reserved.Member)
+	}
+}
+
+func TestExtendedProblem_UnmarshalJSON(t *testing.T) {
+	data := []byte(`{"type":"https://example.com/out-of-credit","title":"Out of credit","status":403,"balance":30,"accounts":["/account/12345"]}`)
+
+	var problem ExtendedProblem[creditProblemExt]
+	if err := json.Unmarshal(data, &problem); err != nil {
+		t.Fatalf("failed to unmarshal extended problem: %s", err)
+	}
+
+	if problem.Type != "https://example.com/out-of-credit" || problem.Status != http.StatusForbidden {
+		t.Errorf("standard members were not decoded: %#+v", problem.Problem)
+	}
+
+	if problem.Extensions.Balance != 30 || len(problem.Extensions.Accounts) != 1 {
+		t.Errorf("extension members were not decoded: %#+v", problem.Extensions)
+	}
+
+	var generic ExtendedProblem[map[string]any]
+	if err := json.Unmarshal(data, &generic); err != nil {
+		t.Fatalf("failed to unmarshal extended problem: %s", err)
+	}
+
+	if _, ok := generic.Extensions["title"]; ok {
+		t.Errorf("standard members should not be decoded as extensions")
+	}
+
+	if len(generic.Extensions) != 2 {
+		t.Errorf("expected 2 extension members, got %d", len(generic.Extensions))
+	}
+}
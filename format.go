@@ -0,0 +1,151 @@
+package problems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A formatMember is an additional line printed by the %+v verb, such as an
+// extension member.
+type formatMember struct {
+	name  string
+	value string
+}
+
+// plainProblem has the same fields as Problem, but no Format method, so that
+// it can be printed using the default Go-syntax representation of the %#v
+// verb.
+type plainProblem Problem
+
+// Format implements the fmt.Formatter interface. The %s and %v verbs print the
+// same short form of the problem as Error, and %q prints it as a quoted string.
+//
+// The %+v verb prints a multi-line description of the problem, which also
+// includes its type, instance, and the chain of errors which caused it, while
+// %#v prints the Go-syntax representation of the problem.
+//
+// Format is promoted to types which embed a Problem, so for a pointer to such a
+// type every verb describes only the embedded Problem, and %#v leaves out the
+// other fields of the embedding type. Print the embedding type by value, or
+// give it a Format method of its own, to include them.
+func (p *Problem) Format(s fmt.State, verb rune) {
+	formatProblem(s, verb, p, p, func() string {
+		return "&" + p.goSyntax()
+	}, nil)
+}
+
+// Format implements the fmt.Formatter interface in the same way as
+// Problem.Format, and includes each extension member in the %+v form.
+func (p *ExtendedProblem[T]) Format(s fmt.State, verb rune) {
+	formatProblem(s, verb, p, &p.Problem, func() string {
+		return fmt.Sprintf("&%s{Problem:%s, Extensions:%#v}", strings.TrimPrefix(fmt.Sprintf("%T", p), "*"), p.Problem.goSyntax(), p.Extensions)
+	}, func() []formatMember {
+		ext, err := json.Marshal(p.Extensions)
+		if err != nil {
+			return []formatMember{{name: "extensions", value: fmt.Sprintf("%%!(%s)", err)}}
+		}
+
+		var members []formatMember
+		for _, m := range jsonMembers(ext) {
+			members = append(members, formatMember{name: m.name, value: string(m.value)})
+		}
+		return members
+	})
+}
+
+// Format implements the fmt.Formatter interface in the same way as
+// Problem.Format, and includes each validation error in the %+v form.
+func (p *ValidationProblem) Format(s fmt.State, verb rune) {
+	formatProblem(s, verb, p, &p.Problem, func() string {
+		return fmt.Sprintf("&problems.ValidationProblem{Problem:%s, Errors:%#v}", p.Problem.goSyntax(), p.Errors)
+	}, func() []formatMember {
+		members := make([]formatMember, 0, len(p.Errors))
+		for _, err := range p.Errors {
+			members = append(members, formatMember{name: "error", value: err.Error()})
+		}
+		return members
+	})
+}
+
+// summary returns the short form of the problem, "title (status) - detail",
+// leaving out any members which are empty.
+func (p *Problem) summary() string {
+	var b strings.Builder
+	b.WriteString(p.Title)
+	if p.Status != 0 {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "(%d)", p.Status)
+	}
+	if p.Detail != "" {
+		if b.Len() > 0 {
+			b.WriteString(" - ")
+		}
+		b.WriteString(p.Detail)
+	}
+	return b.String()
+}
+
+// goSyntax returns the Go-syntax representation of the Problem value p, as
+// printed by the %#v verb.
+func (p *Problem) goSyntax() string {
+	return "problems.Problem" + strings.TrimPrefix(fmt.Sprintf("%#v", plainProblem(*p)), "problems.plainProblem")
+}
+
+// formatProblem implements fmt.Formatter for err, whose standard members are
+// held by p. The goSyntax function returns the representation of err printed by
+// the %#v verb, and the members function, if provided, returns the additional
+// lines printed by the %+v verb.
+func formatProblem(s fmt.State, verb rune, err error, p *Problem, goSyntax func() string, members func() []formatMember) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			io.WriteString(s, goSyntax())
+			return
+		}
+		if s.Flag('+') {
+			io.WriteString(s, p.summary())
+			writeFormatMember(s, formatMember{name: "type", value: p.Type})
+			if p.Instance != "" {
+				writeFormatMember(s, formatMember{name: "instance", value: p.Instance})
+			}
+			if members != nil {
+				for _, m := range members() {
+					writeFormatMember(s, m)
+				}
+			}
+			writeCauses(s, p.cause)
+			return
+		}
+		fmt.Fprintf(s, fmt.FormatString(s, 's'), err.Error())
+	case 's', 'q':
+		fmt.Fprintf(s, fmt.FormatString(s, verb), err.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, err, err.Error())
+	}
+}
+
+// writeFormatMember writes m to w on its own, indented, line.
+func writeFormatMember(w io.Writer, m formatMember) {
+	fmt.Fprintf(w, "\n    %s: %s", m.name, m.value)
+}
+
+// writeCauses writes a line for err, and each of the errors which it wraps, in
+// the order returned by their Unwrap methods.
+func writeCauses(w io.Writer, err error) {
+	for err != nil {
+		writeFormatMember(w, formatMember{name: "cause", value: err.Error()})
+
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multi.Unwrap() {
+				writeCauses(w, e)
+			}
+			return
+		}
+		err = errors.Unwrap(err)
+	}
+}
@@ -0,0 +1,143 @@
+package problems
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestProblem_Format(t *testing.T) {
+	errNoRows := errors.New("sql: no rows in result set")
+	problem := NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusForbidden).
+		WithDetail("Your current balance is 30, but that costs 50.").
+		WithInstance("/account/12345/msgs/abc").
+		WithCause(fmt.Errorf("loading balance: %w", errNoRows)).
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}})
+
+	tests := []struct {
+		name   string
+		format string
+		value  any
+		expect string
+	}{
+		{
+			name:   "should print the short form with %s",
+			format: "%s",
+			value:  NewDetailedProblem(http.StatusNotFound, "Sorry, that user does not exist."),
+			expect: "Not Found (404) - Sorry, that user does not exist.",
+		},
+		{
+			name:   "should leave out empty members",
+			format: "%v",
+			value:  New().WithTitle("Something went wrong"),
+			expect: "Something went wrong",
+		},
+		{
+			name:   "should quote the short form with %q",
+			format: "%q",
+			value:  NewStatusProblem(http.StatusNotFound),
+			expect: `"Not Found (404)"`,
+		},
+		{
+			name:   "should print a problem wrapped by another error",
+			format: "%v",
+			value:  fmt.Errorf("getting user: %w", NewStatusProblem(http.StatusNotFound)),
+			expect: "getting user: Not Found (404)",
+		},
+		{
+			name:   "should print extensions in the short form",
+			format: "%s",
+			value:  problem,
+			expect: `You do not have enough credit. (403) - Your current balance is 30, but that costs 50. - {"balance":30,"accounts":["/account/12345"]}`,
+		},
+		{
+			name:   "should print the verbose form with %+v",
+			format: "%+v",
+			value:  NewStatusProblem(http.StatusNotFound),
+			expect: "Not Found (404)\n    type: about:blank",
+		},
+		{
+			name:   "should print extensions and causes in the verbose form",
+			format: "%+v",
+			value:  problem,
+			expect: "You do not have enough credit. (403) - Your current balance is 30, but that costs 50." +
+				"\n    type: https://example.com/probs/out-of-credit" +
+				"\n    instance: /account/12345/msgs/abc" +
+				"\n    balance: 30" +
+				"\n    accounts: [\"/account/12345\"]" +
+				"\n    cause: loading balance: sql: no rows in result set" +
+				"\n    cause: sql: no rows in result set",
+		},
+		{
+			name:   "should print each of the joined causes in the verbose form",
+			format: "%+v",
+			value:  NewStatusProblem(http.StatusBadGateway).WithCause(errors.Join(errNoRows, http.ErrHandlerTimeout)),
+			expect: "Bad Gateway (502)\n    type: about:blank" +
+				"\n    cause: sql: no rows in result set\nhttp: Handler timeout" +
+				"\n    cause: sql: no rows in result set" +
+				"\n    cause: http: Handler timeout",
+		},
+		{
+			name:   "should print validation errors in the verbose form",
+			format: "%+v",
+			value:  NewValidationProblem().AddPointer("#/age", "must be a positive integer"),
+			expect: "Unprocessable Entity (422)" +
+				"\n    type: about:blank" +
+				"\n    error: #/age: must be a positive integer",
+		},
+		{
+			name:   "should print the Go-syntax representation with %#v",
+			format: "%#v",
+			value:  NewDetailedProblem(http.StatusNotFound, "no such user"),
+			expect: `&problems.Problem{Type:"about:blank", Title:"Not Found", Status:404, Detail:"no such user", Instance:"", cause:error(nil), header:(*http.Header)(nil)}`,
+		},
+		{
+			name:   "should print the Go-syntax representation of extended problems with %#v",
+			format: "%#v",
+			value:  NewExt[map[string]int]().WithTitle("Oops").WithExtension(map[string]int{"a": 1}),
+			expect: `&problems.ExtendedProblem[map[string]int]{Problem:problems.Problem{Type:"about:blank", Title:"Oops", Status:0, Detail:"", Instance:"", cause:error(nil), header:(*http.Header)(nil)}, Extensions:map[string]int{"a":1}}`,
+		},
+		{
+			name:   "should print the Go-syntax representation of validation problems with %#v",
+			format: "%#v",
+			value:  &ValidationProblem{Problem: Problem{Title: "Invalid"}},
+			expect: `&problems.ValidationProblem{Problem:problems.Problem{Type:"", Title:"Invalid", Status:0, Detail:"", Instance:"", cause:error(nil), header:(*http.Header)(nil)}, Errors:[]problems.ValidationError(nil)}`,
+		},
+		{
+			name:   "should report unsupported verbs",
+			format: "%d",
+			value:  NewStatusProblem(http.StatusNotFound),
+			expect: "%!d(*problems.Problem=Not Found (404))",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := fmt.Sprintf(test.format, test.value); got != test.expect {
+				t.Errorf("unexpected output:\ngot\n%s\nwant\n%s", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestProblem_Format_embedded(t *testing.T) {
+	problem := &creditProblem{Problem: *NewStatusProblem(http.StatusForbidden), Balance: 30}
+	standard := `problems.Problem{Type:"about:blank", Title:"Forbidden", Status:403, Detail:"", Instance:"", cause:error(nil), header:(*http.Header)(nil)}`
+
+	// Format is promoted to the pointer type, and only prints the embedded
+	// Problem.
+	if got, expect := fmt.Sprintf("%#v", problem), "&"+standard; got != expect {
+		t.Errorf("unexpected output:\ngot\n%s\nwant\n%s", got, expect)
+	}
+
+	// The value type does not have the method, and keeps the default Go-syntax
+	// representation, including its own fields.
+	expect := `problems.creditProblem{Problem:` + standard + `, Balance:30, Accounts:[]string(nil)}`
+	if got := fmt.Sprintf("%#v", *problem); got != expect {
+		t.Errorf("unexpected output:\ngot\n%s\nwant\n%s", got, expect)
+	}
+}
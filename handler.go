@@ -0,0 +1,71 @@
+package problems
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultWriter is the Writer used by HandlerFunc to write problems.
+var DefaultWriter = &Writer{}
+
+// The HandlerFunc type is an adapter to allow the use of ordinary functions
+// which return an error as HTTP handlers. If the function returns a non-nil
+// error, it is written as a problem using DefaultWriter.
+//
+// If the function has already started writing its response when it returns an
+// error, no problem is written, as it would corrupt the response. The error is
+// instead passed to the OnError function of DefaultWriter, wrapped with
+// ErrResponseStarted.
+//
+// See Writer.WriteError for how errors are converted into problems.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f(w, r) and writes any error it returns as a problem.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	DefaultWriter.Handle(f)(w, r)
+}
+
+// Handle returns a http.HandlerFunc which calls f and writes any error it
+// returns as a problem using the Writer. As with HandlerFunc, errors returned
+// after f has started writing its response are passed to OnError instead.
+func (pw *Writer) Handle(f HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w}
+		err := f(tw, r)
+		if err == nil {
+			return
+		}
+		if tw.wroteHeader {
+			pw.reportError(r, fmt.Errorf("%w: %w", ErrResponseStarted, err))
+			return
+		}
+		_ = pw.WriteError(w, r, err)
+	}
+}
+
+// WriteError writes the provided error to w as a problem.
+//
+// If err is, or wraps, a problem such as a *Problem or *ExtendedProblem then
+// that problem is written as-is. Otherwise, the error is converted into a
+// problem using the Writer's ErrorProblem function, or into a 500 Internal
+// Server Error problem whose cause is err.
+//
+// Any error encountered while writing the problem is returned, as described by
+// Write.
+func (pw *Writer) WriteError(w http.ResponseWriter, r *http.Request, err error) error {
+	return pw.Write(w, r, pw.errorProblem(err))
+}
+
+// errorProblem returns the problem which should be written for err.
+func (pw *Writer) errorProblem(err error) Details {
+	var p Details
+	if errors.As(err, &p) {
+		return p
+	}
+
+	if pw.ErrorProblem != nil {
+		return pw.ErrorProblem(err)
+	}
+	return NewStatusProblem(http.StatusInternalServerError).WithCause(err)
+}
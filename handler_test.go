@@ -0,0 +1,147 @@
+package problems
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       HandlerFunc
+		expectStatus  int
+		expectContent string
+	}{
+		{
+			name: "should not write a problem when no error is returned",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			},
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			name: "should write returned problems as-is",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return NewDetailedProblem(http.StatusNotFound, "no such user")
+			},
+			expectStatus:  http.StatusNotFound,
+			expectContent: `"detail":"no such user"`,
+		},
+		{
+			name: "should write returned extended problems as-is",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return NewExt[creditProblemExt]().
+					WithStatus(http.StatusForbidden).
+					WithExtension(creditProblemExt{Balance: 30})
+			},
+			expectStatus:  http.StatusForbidden,
+			expectContent: `"balance":30`,
+		},
+		{
+			name: "should find wrapped problems",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return fmt.Errorf("loading user: %w", NewStatusProblem(http.StatusConflict))
+			},
+			expectStatus:  http.StatusConflict,
+			expectContent: `"title":"Conflict"`,
+		},
+		{
+			name: "should write other errors as internal server errors",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return errors.New("connection refused")
+			},
+			expectStatus:  http.StatusInternalServerError,
+			expectContent: `"title":"Internal Server Error"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			test.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != test.expectStatus {
+				t.Errorf("Expected HTTP status code to be %d, got %d", test.expectStatus, rec.Code)
+			}
+
+			if !strings.Contains(rec.Body.String(), test.expectContent) {
+				t.Errorf("Expected body to contain %q, got %q", test.expectContent, rec.Body.String())
+			}
+
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Errorf("internal error details should not be written: %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriter_Handle(t *testing.T) {
+	writer := &Writer{
+		ErrorProblem: func(err error) Details {
+			return NewStatusProblem(http.StatusBadGateway).WithType("https://example.com/probs/upstream")
+		},
+	}
+
+	handler := writer.Handle(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("upstream unavailable")
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected HTTP status code to be %d, got %d", http.StatusBadGateway, rec.Code)
+	}
+
+	if !strings.Contains(rec.Body.String(), `"type":"https://example.com/probs/upstream"`) {
+		t.Errorf("Expected body to contain the configured problem, got %q", rec.Body.String())
+	}
+}
+
+func TestWriter_HandleAfterResponseStarted(t *testing.T) {
+	errQuery := errors.New("query interrupted")
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		_, _ = w.Write([]byte(`{"items":[`))
+		return errQuery
+	}
+
+	var reported []error
+	onError := func(r *http.Request, err error) {
+		reported = append(reported, err)
+	}
+
+	original := DefaultWriter
+	DefaultWriter = &Writer{OnError: onError}
+	defer func() { DefaultWriter = original }()
+
+	handlers := map[string]http.HandlerFunc{
+		"HandlerFunc":   HandlerFunc(handler).ServeHTTP,
+		"Writer.Handle": (&Writer{OnError: onError}).Handle(handler),
+	}
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			reported = nil
+			rec := httptest.NewRecorder()
+			h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected HTTP status code to be %d, got %d", http.StatusOK, rec.Code)
+			}
+			if body := rec.Body.String(); body != `{"items":[` {
+				t.Errorf("Expected no problem to be written after the response started, got %q", body)
+			}
+
+			if len(reported) != 1 {
+				t.Fatalf("Expected 1 error to be reported, got %d: %v", len(reported), reported)
+			}
+			if !errors.Is(reported[0], ErrResponseStarted) || !errors.Is(reported[0], errQuery) {
+				t.Errorf("Expected the handler's error to be reported, got %v", reported[0])
+			}
+		})
+	}
+}
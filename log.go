@@ -0,0 +1,133 @@
+package problems
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// StatusLevel returns the level at which a problem with the provided status is
+// logged by default. Server errors are logged at slog.LevelError, client
+// errors at slog.LevelWarn, and all other problems at slog.LevelInfo.
+func StatusLevel(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// LogValue implements the slog.LogValuer interface, and logs the problem as a
+// group of its non-empty members, along with its cause.
+func (p *Problem) LogValue() slog.Value {
+	return slog.GroupValue(p.logAttrs()...)
+}
+
+// LogValue implements the slog.LogValuer interface, and logs the problem as a
+// group of its non-empty members, including its extension members, along with
+// its cause.
+func (p *ExtendedProblem[T]) LogValue() slog.Value {
+	attrs := p.Problem.logAttrs()
+	if ext, err := json.Marshal(p.Extensions); err == nil {
+		attrs = append(attrs, jsonAttrs(ext)...)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements the slog.LogValuer interface, and logs the problem as a
+// group of its non-empty members, including its validation errors, along with
+// its cause.
+func (p *ValidationProblem) LogValue() slog.Value {
+	attrs := p.Problem.logAttrs()
+	if len(p.Errors) > 0 {
+		errs := make([]string, 0, len(p.Errors))
+		for _, err := range p.Errors {
+			errs = append(errs, err.Error())
+		}
+		attrs = append(attrs, slog.Any("errors", errs))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements the slog.LogValuer interface, and logs the problem as a
+// group of its non-empty members.
+func (p *ValidProblem) LogValue() slog.Value {
+	return p.IntoProblem().LogValue()
+}
+
+// LogValue implements the slog.LogValuer interface, and logs the problem as a
+// group of its non-empty members, including its extension members.
+func (p *ValidExtendedProblem[T]) LogValue() slog.Value {
+	return p.IntoExtendedProblem().LogValue()
+}
+
+// logAttrs returns the attributes used to log the standard members of the
+// problem, and its cause.
+func (p *Problem) logAttrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("type", p.Type),
+		slog.String("title", p.Title),
+	}
+	if p.Status != 0 {
+		attrs = append(attrs, slog.Int("status", p.Status))
+	}
+	if p.Detail != "" {
+		attrs = append(attrs, slog.String("detail", p.Detail))
+	}
+	if p.Instance != "" {
+		attrs = append(attrs, slog.String("instance", p.Instance))
+	}
+	if p.cause != nil {
+		attrs = append(attrs, slog.String("cause", p.cause.Error()))
+	}
+	return attrs
+}
+
+// jsonAttrs returns an attribute for each member of the provided JSON object,
+// in the order in which they appear.
+func jsonAttrs(data []byte) []slog.Attr {
+	var attrs []slog.Attr
+	for _, m := range jsonMembers(data) {
+		var value any
+		if err := json.Unmarshal(m.value, &value); err != nil {
+			continue
+		}
+		attrs = append(attrs, slog.Any(m.name, value))
+	}
+	return attrs
+}
+
+// logValue returns the value used to log the provided problem.
+func logValue(p Details) slog.Value {
+	if v, ok := p.(slog.LogValuer); ok {
+		return v.LogValue()
+	}
+	return p.details().LogValue()
+}
+
+// log logs the problem being written in response to r, if the Writer has a
+// Logger.
+func (pw *Writer) log(r *http.Request, p Details, id string) {
+	if pw.Logger == nil {
+		return
+	}
+
+	level := StatusLevel
+	if pw.LogLevel != nil {
+		level = pw.LogLevel
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	}
+	if id != "" {
+		attrs = append(attrs, slog.String("correlation_id", id))
+	}
+	attrs = append(attrs, slog.Attr{Key: "problem", Value: logValue(p)})
+
+	pw.Logger.LogAttrs(r.Context(), level(p.details().Status), "serving problem", attrs...)
+}
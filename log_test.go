@@ -0,0 +1,122 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProblem_LogValue(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusForbidden).
+		WithError(errors.New("balance is 30, but that costs 50")).
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}})
+
+	valid, err := problem.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		value  slog.LogValuer
+		expect string
+	}{
+		{
+			name:   "should log problems",
+			value:  NewStatusProblem(http.StatusNotFound),
+			expect: `{"problem":{"type":"about:blank","title":"Not Found","status":404}}`,
+		},
+		{
+			name:   "should log extended problems",
+			value:  problem,
+			expect: `{"problem":{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403,"detail":"balance is 30, but that costs 50","cause":"balance is 30, but that costs 50","balance":30,"accounts":["/account/12345"]}}`,
+		},
+		{
+			name:   "should log valid problems",
+			value:  valid,
+			expect: `{"problem":{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403,"detail":"balance is 30, but that costs 50","balance":30,"accounts":["/account/12345"]}}`,
+		},
+		{
+			name:   "should log validation problems",
+			value:  NewValidationProblem().AddPointer("#/age", "must be a positive integer"),
+			expect: `{"problem":{"type":"about:blank","title":"Unprocessable Entity","status":422,"errors":["#/age: must be a positive integer"]}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+
+			logger.Info("", "problem", test.value)
+
+			if got := strings.TrimSpace(buf.String()); got != test.expect {
+				t.Errorf("unexpected log output:\ngot\n%s\nwant\n%s", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestWriter_Logger(t *testing.T) {
+	tests := []struct {
+		name        string
+		problem     Details
+		expectLevel string
+	}{
+		{name: "should log server errors as errors", problem: NewStatusProblem(http.StatusBadGateway), expectLevel: "ERROR"},
+		{name: "should log client errors as warnings", problem: NewStatusProblem(http.StatusNotFound), expectLevel: "WARN"},
+		{name: "should log other problems as info", problem: New().WithTitle("Moved"), expectLevel: "INFO"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer := &Writer{
+				Logger:      slog.New(slog.NewJSONHandler(&buf, nil)),
+				Redaction:   &RedactionPolicy{RedactServerErrors: true},
+				Correlation: &Correlation{Generate: func() string { return "abc123" }},
+			}
+
+			problem := modify(test.problem, func(p *Problem) { p.Detail = "internal detail" })
+			writer.Write(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil), problem)
+
+			var entry struct {
+				Level         string         `json:"level"`
+				Msg           string         `json:"msg"`
+				Path          string         `json:"path"`
+				CorrelationID string         `json:"correlation_id"`
+				Problem       map[string]any `json:"problem"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("failed to decode log entry %q: %s", buf.String(), err)
+			}
+
+			if entry.Level != test.expectLevel {
+				t.Errorf("expected level %q, got %q", test.expectLevel, entry.Level)
+			}
+
+			if entry.Path != "/users/1" || entry.CorrelationID != "abc123" {
+				t.Errorf("expected request attributes to be logged, got %q", buf.String())
+			}
+
+			if entry.Problem["detail"] != "internal detail" {
+				t.Errorf("expected the problem to be logged before redaction, got %q", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+)
+
+// modify returns a copy of p after applying fn to the copy's standard members.
+// p itself is never modified.
+//
+// The copy wraps p rather than being a new *Problem, so that every other
+// member of p, including the fields of types which embed a Problem, is retained
+// when the copy is serialized.
+func modify(p Details, fn func(*Problem)) Details {
+	problem := p.details().Clone()
+	fn(problem)
+	return &modified{Details: p, problem: problem}
+}
+
+// modified overrides the standard members of the problem it wraps when it is
+// serialized.
+type modified struct {
+	Details
+	problem *Problem
+}
+
+// details implements the Details interface.
+func (p *modified) details() *Problem {
+	return p.problem
+}
+
+// Error implements the error interface.
+func (p *modified) Error() string {
+	return p.problem.Error()
+}
+
+// Unwrap returns the underlying error which caused the modified problem, if
+// any.
+func (p *modified) Unwrap() error {
+	return p.problem.Unwrap()
+}
+
+// MarshalJSON implements the json.Marshaler interface. The standard members
+// are followed by every other member of the wrapped problem, in the order in
+// which it serializes them.
+func (p *modified) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(p.problem)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := json.Marshal(p.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	var ext bytes.Buffer
+	ext.WriteByte('{')
+	for _, m := range jsonMembers(original) {
+		if _, ok := reservedMembers[m.name]; ok {
+			continue
+		}
+		if ext.Len() > 1 {
+			ext.WriteByte(',')
+		}
+		name, _ := json.Marshal(m.name)
+		ext.Write(name)
+		ext.WriteByte(':')
+		ext.Write(m.value)
+	}
+	ext.WriteByte('}')
+
+	return mergeMembers(base, ext.Bytes())
+}
+
+// MarshalXML implements the xml.Marshaler interface. The standard members are
+// followed by every other child element of the wrapped problem.
+func (p *modified) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	var buf bytes.Buffer
+	if err := encodeXML(&buf, p.Details); err != nil {
+		return err
+	}
+
+	d := xml.NewDecoder(&buf)
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 1 {
+				if err := e.EncodeToken(xmlRoot); err != nil {
+					return err
+				}
+				if err := encodeStandardMembers(e, p.problem); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, ok := reservedMembers[t.Name.Local]; ok && depth == 2 {
+				depth--
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			tok = xml.StartElement{Name: xml.Name{Local: t.Name.Local}}
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				return e.EncodeToken(xmlRoot.End())
+			}
+			tok = xml.EndElement{Name: xml.Name{Local: t.Name.Local}}
+		case xml.ProcInst, xml.Directive:
+			continue
+		}
+
+		if err := e.EncodeToken(xml.CopyToken(tok)); err != nil {
+			return err
+		}
+	}
+}
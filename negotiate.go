@@ -0,0 +1,114 @@
+package problems
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// defaultOffers are the media types, in order of preference, which a Writer
+// will produce when no Offers are configured.
+var defaultOffers = []string{
+	ProblemMediaType,
+	ProblemMediaTypeXML,
+	JSONMediaType,
+	HTMLMediaType,
+	ConciseProblemMediaType,
+}
+
+// A mediaRange is a single, parsed, element of an Accept header.
+type mediaRange struct {
+	typ     string
+	subtype string
+	quality float64
+}
+
+// specificity returns how specific the media range is, so that more specific
+// ranges take precedence over wildcards when they both match a media type.
+func (m mediaRange) specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matches reports whether the media range includes the provided media type.
+func (m mediaRange) matches(typ, subtype string) bool {
+	return (m.typ == "*" || m.typ == typ) && (m.subtype == "*" || m.subtype == subtype)
+}
+
+// parseAccept parses the provided Accept header values into a list of media
+// ranges. Malformed elements are ignored.
+func parseAccept(values []string) []mediaRange {
+	var ranges []mediaRange
+	for _, value := range values {
+		for _, elem := range strings.Split(value, ",") {
+			if strings.TrimSpace(elem) == "" {
+				continue
+			}
+
+			mediaType, params, err := mime.ParseMediaType(elem)
+			if err != nil {
+				continue
+			}
+
+			typ, subtype, ok := strings.Cut(mediaType, "/")
+			if !ok {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				quality, err = strconv.ParseFloat(q, 64)
+				if err != nil || quality < 0 || quality > 1 {
+					continue
+				}
+			}
+
+			ranges = append(ranges, mediaRange{typ: typ, subtype: subtype, quality: quality})
+		}
+	}
+	return ranges
+}
+
+// negotiate selects the offer most preferred by the provided Accept header
+// values. Offers are assumed to be in the server's order of preference, which
+// is used to break ties between equally acceptable offers.
+//
+// If no Accept header was provided then the first offer is returned. If none
+// of the offers are acceptable then negotiate returns false.
+func negotiate(accept []string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return offers[0], true
+	}
+
+	var (
+		best        string
+		bestQuality float64
+	)
+	for _, offer := range offers {
+		typ, subtype, _ := strings.Cut(offer, "/")
+
+		quality, specificity := 0.0, -1
+		for _, r := range ranges {
+			if r.matches(typ, subtype) && r.specificity() > specificity {
+				quality, specificity = r.quality, r.specificity()
+			}
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+
+	return best, bestQuality > 0
+}
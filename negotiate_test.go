@@ -0,0 +1,87 @@
+package problems
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept []string
+		offers []string
+		expect string
+		ok     bool
+	}{
+		{
+			name:   "should use first offer without an accept header",
+			offers: defaultOffers,
+			expect: ProblemMediaType,
+			ok:     true,
+		},
+		{
+			name:   "should match exact media type",
+			accept: []string{"application/problem+xml"},
+			offers: defaultOffers,
+			expect: ProblemMediaTypeXML,
+			ok:     true,
+		},
+		{
+			name:   "should prefer higher quality values",
+			accept: []string{"application/problem+json;q=0.5, application/problem+xml;q=0.8"},
+			offers: defaultOffers,
+			expect: ProblemMediaTypeXML,
+			ok:     true,
+		},
+		{
+			name:   "should break ties using offer order",
+			accept: []string{"application/*"},
+			offers: defaultOffers,
+			expect: ProblemMediaType,
+			ok:     true,
+		},
+		{
+			name:   "should prefer specific ranges over wildcards",
+			accept: []string{"*/*;q=0.1, text/html"},
+			offers: defaultOffers,
+			expect: HTMLMediaType,
+			ok:     true,
+		},
+		{
+			name:   "should honor explicit rejections",
+			accept: []string{"application/*, application/problem+json;q=0"},
+			offers: defaultOffers,
+			expect: ProblemMediaTypeXML,
+			ok:     true,
+		},
+		{
+			name:   "should combine multiple accept headers",
+			accept: []string{"text/plain", "application/json"},
+			offers: defaultOffers,
+			expect: JSONMediaType,
+			ok:     true,
+		},
+		{
+			name:   "should ignore malformed media ranges",
+			accept: []string{"garbage;;, application/json;q=abc, application/problem+xml"},
+			offers: defaultOffers,
+			expect: ProblemMediaTypeXML,
+			ok:     true,
+		},
+		{
+			name:   "should fail when nothing is acceptable",
+			accept: []string{"image/png"},
+			offers: defaultOffers,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := negotiate(test.accept, test.offers)
+			if ok != test.ok {
+				t.Errorf("expected ok to be %t, got %t", test.ok, ok)
+			}
+
+			if got != test.expect {
+				t.Errorf("expected media type %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package problems
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 const (
@@ -12,10 +16,30 @@ const (
 	// ProblemMediaTypeXML is the XML variant on the Problem Media type
 	ProblemMediaTypeXML = "application/problem+xml"
 
+	// JSONMediaType is the generic JSON media type, which may be used to serve
+	// a Problem to clients which do not understand ProblemMediaType
+	JSONMediaType = "application/json"
+
+	// HTMLMediaType is the media type used to serve a Problem as a
+	// human-readable HTML page
+	HTMLMediaType = "text/html"
+
+	// ConciseProblemMediaType is the media type for the CBOR representation
+	// of a Problem, as defined by RFC-9290
+	ConciseProblemMediaType = "application/concise-problem-details+cbor"
+
 	// DefaultURL is the default url to use for problem types
 	DefaultURL = "about:blank"
 )
 
+// Details is implemented by every problem type in this package, as well as by
+// any type which embeds one of them, and allows a problem of any kind to be
+// written by the content negotiating Writer.
+type Details interface {
+	// details returns the standard problem details members of the problem.
+	details() *Problem
+}
+
 // A Problem defines all the standard problem detail fields as defined by
 // RFC-9457 and can easily be serialized to either JSON or XML.
 //
@@ -41,6 +65,17 @@ type Problem struct {
 	// A URI that identifies the specific occurrence of the problem. This URI
 	// may or may not yield further information if de-referenced.
 	Instance string `json:"instance,omitempty" xml:"instance,omitempty"`
+
+	// cause is the underlying error which caused the problem, if any. It is
+	// never serialized.
+	cause error
+
+	// header contains the header fields which are written along with the
+	// problem by the HTTP handlers, such as Allow or Retry-After. It is never
+	// serialized. The header is replaced, rather than modified, whenever a
+	// field is set, so that copies of the problem never share changes and
+	// Problem remains comparable.
+	header *http.Header
 }
 
 // New returns a new Problem instance with the type field set to DefaultURL.
@@ -49,7 +84,8 @@ func New() *Problem {
 }
 
 // FromError returns a new Problem instance which contains the string version
-// of the provided error as the details of the problem.
+// of the provided error as the details of the problem. The error is retained as
+// the cause of the problem.
 func FromError(err error) *Problem {
 	return New().WithError(err)
 }
@@ -105,9 +141,19 @@ func (p *Problem) WithDetailf(format string, args ...interface{}) *Problem {
 	return p
 }
 
-// WithError sets the detail message to the provided error.
+// WithError sets the detail message to the provided error, and retains the
+// error as the cause of the problem.
 func (p *Problem) WithError(err error) *Problem {
 	p.Detail = err.Error()
+	p.cause = err
+	return p
+}
+
+// WithCause sets the underlying error which caused the problem, without
+// changing its detail message. The cause is returned by Unwrap, but is never
+// serialized.
+func (p *Problem) WithCause(err error) *Problem {
+	p.cause = err
 	return p
 }
 
@@ -117,8 +163,109 @@ func (p *Problem) WithInstance(instance string) *Problem {
 	return p
 }
 
+// WithHeader sets the named header field to the provided values, replacing
+// any existing values. The header fields of a problem are written along with
+// it by each of the HTTP handlers, but are never serialized.
+func (p *Problem) WithHeader(name string, values ...string) *Problem {
+	header := p.Header()
+	if header == nil {
+		header = http.Header{}
+	}
+	header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	p.header = &header
+	return p
+}
+
+// WithRetryAfter sets the Retry-After header field to the provided delay, in
+// whole seconds rounded up. If the delay is not positive then the header field
+// is removed.
+func (p *Problem) WithRetryAfter(d time.Duration) *Problem {
+	if d > 0 {
+		return p.WithHeader("Retry-After", formatRetryAfter(d))
+	}
+	if header := p.Header(); header.Values("Retry-After") != nil {
+		header.Del("Retry-After")
+		p.header = &header
+	}
+	return p
+}
+
+// Header returns a copy of the header fields which are written along with the
+// problem by the HTTP handlers, or nil if there are none.
+func (p *Problem) Header() http.Header {
+	if p.header == nil {
+		return nil
+	}
+	return p.header.Clone()
+}
+
+// Clone returns a copy of the Problem which can be modified without affecting
+// the original.
+func (p *Problem) Clone() *Problem {
+	c := *p
+	return &c
+}
+
 // Error implements the error interface and allows a Problem to be used as a
-// native error.
+// native error. The message has the form "title (status) - detail", leaving
+// out any members which are empty.
 func (p *Problem) Error() string {
-	return fmt.Sprintf("%s (%d) - %s", p.Title, p.Status, p.Detail)
+	return p.summary()
+}
+
+// Unwrap returns the underlying error which caused the problem, if any.
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+// Is reports whether the Problem matches the target error, allowing problems
+// to be compared with errors.Is.
+//
+// A Problem matches any other problem with the same type URI. Since every
+// "about:blank" problem shares the same type, they must also have the same
+// status to match.
+func (p *Problem) Is(target error) bool {
+	t, ok := target.(Details)
+	if !ok {
+		return false
+	}
+	return sameKind(p, t.details())
+}
+
+// details implements the Details interface.
+func (p *Problem) details() *Problem {
+	return p
+}
+
+// As finds the first problem in err's tree of wrapped errors, such as a
+// *Problem or *ExtendedProblem, and returns its standard members.
+func As(err error) (*Problem, bool) {
+	var d Details
+	if !errors.As(err, &d) {
+		return nil, false
+	}
+	return d.details(), true
+}
+
+// sameKind reports whether the two problems are occurrences of the same kind of
+// problem.
+func sameKind(a, b *Problem) bool {
+	aType, bType := a.Type, b.Type
+	if aType == "" {
+		aType = DefaultURL
+	}
+	if bType == "" {
+		bType = DefaultURL
+	}
+
+	if aType != bType {
+		return false
+	}
+	return aType != DefaultURL || a.Status == b.Status
+}
+
+// formatRetryAfter formats the provided delay as the value of a Retry-After
+// header field, in whole seconds rounded up.
+func formatRetryAfter(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
 }
@@ -3,9 +3,13 @@ package problems
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestProblem(t *testing.T) {
@@ -117,3 +121,184 @@ func TestProblem(t *testing.T) {
 		})
 	}
 }
+
+func TestProblem_Is(t *testing.T) {
+	outOfCredit := New().WithType("https://example.com/probs/out-of-credit").WithTitle("Out of credit")
+	notFound := NewStatusTemplate(http.StatusNotFound)
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		expect bool
+	}{
+		{
+			name:   "should match problems with the same type",
+			err:    outOfCredit.Clone().WithDetail("balance is 30").WithStatus(http.StatusForbidden),
+			target: outOfCredit,
+			expect: true,
+		},
+		{
+			name:   "should match wrapped extended problems",
+			err:    fmt.Errorf("charging account: %w", Extend(outOfCredit.Clone(), creditProblemExt{Balance: 30})),
+			target: outOfCredit,
+			expect: true,
+		},
+		{
+			name:   "should not match problems with different types",
+			err:    New().WithType("https://example.com/probs/other").WithTitle("Out of credit"),
+			target: outOfCredit,
+		},
+		{
+			name:   "should match about:blank problems with the same status",
+			err:    notFound.WithDetail("no such user"),
+			target: notFound,
+			expect: true,
+		},
+		{
+			name:   "should treat an empty type as about:blank",
+			err:    &Problem{Title: "Not Found", Status: http.StatusNotFound},
+			target: NewStatusProblem(http.StatusNotFound),
+			expect: true,
+		},
+		{
+			name:   "should not match about:blank problems with different statuses",
+			err:    NewStatusProblem(http.StatusConflict),
+			target: notFound,
+		},
+		{
+			name:   "should not match other errors",
+			err:    NewStatusProblem(http.StatusNotFound),
+			target: errors.New("Not Found (404)"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := errors.Is(test.err, test.target); got != test.expect {
+				t.Errorf("expected errors.Is to return %t, got %t", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestAs(t *testing.T) {
+	err := fmt.Errorf("handling request: %w", NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithStatus(http.StatusForbidden))
+
+	problem, ok := As(err)
+	if !ok {
+		t.Fatal("expected to find a problem in the error chain")
+	}
+
+	if problem.Type != "https://example.com/probs/out-of-credit" || problem.Status != http.StatusForbidden {
+		t.Errorf("unexpected problem found: %#+v", problem)
+	}
+
+	if _, ok := As(errors.New("not a problem")); ok {
+		t.Error("expected no problem to be found")
+	}
+}
+
+func TestProblem_Unwrap(t *testing.T) {
+	errNoRows := errors.New("sql: no rows in result set")
+
+	tests := []struct {
+		name    string
+		problem error
+		detail  string
+	}{
+		{
+			name:    "should retain errors passed to FromError",
+			problem: FromError(fmt.Errorf("loading user: %w", errNoRows)).WithStatus(http.StatusNotFound),
+			detail:  "loading user: sql: no rows in result set",
+		},
+		{
+			name:    "should retain causes without changing the detail",
+			problem: NewDetailedProblem(http.StatusNotFound, "no such user").WithCause(errNoRows),
+			detail:  "no such user",
+		},
+		{
+			name:    "should retain causes of extended problems",
+			problem: ExtFromError[map[string]any](errNoRows).WithStatus(http.StatusNotFound),
+			detail:  errNoRows.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !errors.Is(test.problem, errNoRows) {
+				t.Errorf("expected problem to wrap %v", errNoRows)
+			}
+
+			data, err := json.Marshal(test.problem)
+			if err != nil {
+				t.Fatalf("Error marshalling problem data: %s", err)
+			}
+
+			var decoded map[string]any
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Error unmarshalling problem data: %s", err)
+			}
+
+			if decoded["detail"] != test.detail || len(decoded) != 4 {
+				t.Errorf("unexpected serialized problem: %s", data)
+			}
+		})
+	}
+}
+
+func TestErrInvalidProblemType_Unwrap(t *testing.T) {
+	_, err := New().WithType("::/").WithTitle("Invalid").Validate()
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("expected the url parsing error to be unwrapped, got %v", err)
+	}
+}
+
+func TestProblem_WithHeader(t *testing.T) {
+	problem := NewStatusProblem(http.StatusServiceUnavailable).
+		WithHeader("cache-control", "no-store").
+		WithHeader("Link", `</status>; rel="help"`, `</docs>; rel="describedby"`).
+		WithRetryAfter(90 * time.Second)
+
+	expect := http.Header{
+		"Cache-Control": {"no-store"},
+		"Link":          {`</status>; rel="help"`, `</docs>; rel="describedby"`},
+		"Retry-After":   {"90"},
+	}
+	if header := problem.Header(); !reflect.DeepEqual(header, expect) {
+		t.Errorf("unexpected header: %v", header)
+	}
+
+	problem.Header().Set("Retry-After", "1")
+	clone := problem.Clone().WithRetryAfter(0).WithHeader("Cache-Control", "no-cache")
+	if header := problem.Header(); !reflect.DeepEqual(header, expect) {
+		t.Errorf("modifying a copy of the header modified the original problem: %v", header)
+	}
+	if header := clone.Header(); header.Get("Retry-After") != "" || header.Get("Cache-Control") != "no-cache" {
+		t.Errorf("unexpected header of clone: %v", header)
+	}
+
+	if data, _ := json.Marshal(problem); strings.Contains(string(data), "no-store") {
+		t.Errorf("expected the header to not be serialized, got %s", data)
+	}
+}
+
+func TestProblem_Comparable(t *testing.T) {
+	seen := map[Problem]bool{*NewStatusProblem(http.StatusNotFound): true}
+	if !seen[*NewStatusProblem(http.StatusNotFound)] {
+		t.Error("expected problems without header fields to be equal")
+	}
+
+	problem := NewStatusProblem(http.StatusServiceUnavailable).WithRetryAfter(time.Second)
+	clone := problem.Clone()
+	if *problem != *clone {
+		t.Error("expected a clone to be equal to the original problem")
+	}
+	if clone.WithRetryAfter(time.Minute); *problem == *clone {
+		t.Error("expected changing the header of a clone to make it unequal")
+	}
+}
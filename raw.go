@@ -0,0 +1,30 @@
+package problems
+
+import "encoding/json"
+
+// A RawProblem is an ExtendedProblem which retains every non-standard member
+// of a decoded problem details object as raw JSON.
+//
+// RawProblem is useful when the extension members of a problem are not known
+// ahead of time, such as when relaying problems returned from an upstream
+// service. Decoding a problem into a RawProblem and encoding it again will
+// preserve the value of each of its extension members. As the members are held
+// in a map, their order is not preserved, and they are encoded after the
+// standard members, sorted by name.
+type RawProblem = ExtendedProblem[map[string]json.RawMessage]
+
+// NewRaw returns a new RawProblem with all the same default values as applied
+// by a call to New.
+func NewRaw() *RawProblem {
+	return NewExt[map[string]json.RawMessage]()
+}
+
+// ParseRaw decodes the provided problem details JSON document into a new
+// RawProblem instance.
+func ParseRaw(data []byte) (*RawProblem, error) {
+	p := NewRaw()
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
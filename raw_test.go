@@ -0,0 +1,57 @@
+package problems
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseRaw(t *testing.T) {
+	data := `{"type":"https://example.com/out-of-credit","title":"You do not have enough credit.","status":403,"detail":"Your current balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","accounts":["/account/12345","/account/67890"],"balance":30,"nested":{"a":[1,2,{"b":null}]}}`
+
+	problem, err := ParseRaw([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to parse raw problem: %s", err)
+	}
+
+	if problem.Title != "You do not have enough credit." {
+		t.Errorf("expected title to be decoded, got %q", problem.Title)
+	}
+
+	if len(problem.Extensions) != 3 {
+		t.Errorf("expected 3 extension members, got %d", len(problem.Extensions))
+	}
+
+	if string(problem.Extensions["nested"]) != `{"a":[1,2,{"b":null}]}` {
+		t.Errorf("nested extension member was not preserved: %s", problem.Extensions["nested"])
+	}
+
+	encoded, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("failed to marshal raw problem: %s", err)
+	}
+
+	// The values of the extension members are retained, but they are written
+	// in order of their names.
+	expect := `{"type":"https://example.com/out-of-credit","title":"You do not have enough credit.","status":403,"detail":"Your current balance is 30, but that costs 50.","instance":"/account/12345/msgs/abc","accounts":["/account/12345","/account/67890"],"balance":30,"nested":{"a":[1,2,{"b":null}]}}`
+	if string(encoded) != expect {
+		t.Errorf("raw problem did not round trip:\ngot\n%s\nwant\n%s", encoded, expect)
+	}
+
+	reordered, err := ParseRaw([]byte(`{"title":"Conflict","z":1,"a":{"y":2,"x":3}}`))
+	if err != nil {
+		t.Fatalf("failed to parse raw problem: %s", err)
+	}
+	encoded, err = json.Marshal(reordered)
+	if err != nil {
+		t.Fatalf("failed to marshal raw problem: %s", err)
+	}
+	if expect := `{"type":"about:blank","title":"Conflict","a":{"y":2,"x":3},"z":1}`; string(encoded) != expect {
+		t.Errorf("expected members to be sorted by name with unchanged values:\ngot\n%s\nwant\n%s", encoded, expect)
+	}
+}
+
+func TestParseRaw_invalid(t *testing.T) {
+	if _, err := ParseRaw([]byte(`["not", "a", "problem"]`)); err == nil {
+		t.Error("expected an error parsing a non-object document")
+	}
+}
@@ -0,0 +1,98 @@
+package problems
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover returns a http.Handler which recovers from any panic raised by next
+// and responds with a 500 Internal Server Error problem. See Recoverer for more
+// information.
+func Recover(next http.Handler) http.Handler {
+	return (&Recoverer{}).Middleware(next)
+}
+
+// A Recoverer recovers from panics raised by HTTP handlers and responds with a
+// 500 Internal Server Error problem in their place.
+//
+// Panics with the value http.ErrAbortHandler are re-raised, so that the server
+// can abort the response as intended. If the handler had already started
+// writing its response when it panicked, no problem is written, and the panic
+// is instead re-raised as http.ErrAbortHandler so that the server aborts the
+// response rather than ending it as though it were complete.
+type Recoverer struct {
+	// Writer is used to write the problem. If nil, DefaultWriter is used.
+	Writer *Writer
+
+	// OnPanic, if set, is called with the recovered value and the stack trace
+	// of the panicking goroutine before the problem is written, or before the
+	// response is aborted.
+	OnPanic func(r *http.Request, v any, stack []byte)
+}
+
+// Middleware returns a http.Handler which calls next, recovering from any panic
+// that it raises.
+func (rc *Recoverer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			if rc.OnPanic != nil {
+				rc.OnPanic(r, v, debug.Stack())
+			}
+			if tw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+
+			writer := rc.Writer
+			if writer == nil {
+				writer = DefaultWriter
+			}
+			_ = writer.Write(w, r, NewStatusProblem(http.StatusInternalServerError))
+		}()
+
+		next.ServeHTTP(tw, r)
+	})
+}
+
+// A trackingWriter is a http.ResponseWriter which records whether the response
+// headers have been written.
+type trackingWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader implements the http.ResponseWriter interface.
+func (w *trackingWriter) WriteHeader(code int) {
+	// Informational responses do not commit the final response headers.
+	if code >= http.StatusOK {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements the http.ResponseWriter interface.
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements the http.Flusher interface, if the underlying
+// http.ResponseWriter supports flushing.
+func (w *trackingWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying http.ResponseWriter, allowing it to be used by
+// a http.ResponseController.
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,103 @@
+package problems
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectStatus  int
+		expectContent string
+		expectAbort   bool
+	}{
+		{
+			name: "should write a problem when a handler panics",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic("something bad happened")
+			},
+			expectStatus:  http.StatusInternalServerError,
+			expectContent: `"title":"Internal Server Error"`,
+		},
+		{
+			name: "should abort the response instead of writing a problem after it has started",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("partial"))
+				panic("something bad happened")
+			},
+			expectStatus:  http.StatusAccepted,
+			expectContent: "partial",
+			expectAbort:   true,
+		},
+		{
+			name: "should not interfere with handlers which do not panic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			expectStatus:  http.StatusOK,
+			expectContent: "ok",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var recovered any
+			recoverer := &Recoverer{
+				OnPanic: func(r *http.Request, v any, stack []byte) {
+					recovered = v
+					if len(stack) == 0 {
+						t.Error("expected a stack trace to be provided")
+					}
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			func() {
+				defer func() {
+					v := recover()
+					if test.expectAbort && v != http.ErrAbortHandler {
+						t.Errorf("expected the response to be aborted with http.ErrAbortHandler, got %v", v)
+					} else if !test.expectAbort && v != nil {
+						t.Errorf("expected the panic to be recovered, got %v", v)
+					}
+				}()
+				recoverer.Middleware(test.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			}()
+
+			if rec.Code != test.expectStatus {
+				t.Errorf("Expected HTTP status code to be %d, got %d", test.expectStatus, rec.Code)
+			}
+
+			body := rec.Body.String()
+			if !strings.Contains(body, test.expectContent) {
+				t.Errorf("Expected body to contain %q, got %q", test.expectContent, body)
+			}
+
+			if test.expectStatus != http.StatusInternalServerError && strings.Contains(body, "Internal Server Error") {
+				t.Errorf("Expected no problem to be written, got %q", body)
+			}
+
+			if test.expectStatus != http.StatusOK && recovered != "something bad happened" {
+				t.Errorf("expected OnPanic to receive the panic value, got %v", recovered)
+			}
+		})
+	}
+}
+
+func TestRecover_abortHandler(t *testing.T) {
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to be re-raised, got %v", v)
+		}
+	}()
+
+	handler := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
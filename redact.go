@@ -0,0 +1,110 @@
+package problems
+
+import (
+	"errors"
+	"net/http"
+)
+
+// DefaultRedactionPolicy is the RedactionPolicy used by Writers which do not
+// have a RedactionPolicy of their own. It is nil by default, which disables
+// redaction.
+//
+// Production services will typically set this once during start up:
+//
+//	problems.DefaultRedactionPolicy = &problems.RedactionPolicy{
+//		RedactServerErrors: true,
+//		RedactErrorDetails: true,
+//	}
+var DefaultRedactionPolicy *RedactionPolicy
+
+// A RedactionPolicy controls which problem details are safe to expose to
+// clients. Problems which are redacted have their detail replaced, while the
+// original problem is retained as the cause of the redacted problem so that it
+// can still be logged.
+//
+// Errors which have been marked with Safe, or which are allowed by IsSafe, are
+// never redacted.
+type RedactionPolicy struct {
+	// RedactServerErrors redacts the detail of every problem with a 5xx
+	// status code.
+	RedactServerErrors bool
+
+	// RedactErrorDetails redacts the detail of every problem whose detail was
+	// taken from an error, such as those created with FromError or WithError.
+	RedactErrorDetails bool
+
+	// Detail is the message which replaces redacted details. If empty, the
+	// detail is removed from redacted problems.
+	Detail string
+
+	// IsSafe, if set, reports whether the cause of a problem is safe to
+	// expose. It is consulted in addition to errors marked with Safe.
+	IsSafe func(err error) bool
+}
+
+// Redact returns a copy of the provided problem with its detail redacted, if
+// required by the policy. Otherwise, the problem is returned unchanged. A nil
+// RedactionPolicy never redacts.
+func (rp *RedactionPolicy) Redact(p Details) Details {
+	if rp == nil || !rp.shouldRedact(p.details()) {
+		return p
+	}
+
+	cause := asError(p)
+	return modify(p, func(c *Problem) {
+		c.Detail = rp.Detail
+		c.cause = cause
+	})
+}
+
+// shouldRedact reports whether the detail of the problem must be redacted.
+func (rp *RedactionPolicy) shouldRedact(p *Problem) bool {
+	if p.Detail == "" || rp.isSafe(p.cause) {
+		return false
+	}
+
+	serverError := p.Status >= http.StatusInternalServerError
+	fromError := p.cause != nil && p.Detail == p.cause.Error()
+	return (rp.RedactServerErrors && serverError) || (rp.RedactErrorDetails && fromError)
+}
+
+// isSafe reports whether err has been marked as safe to expose.
+func (rp *RedactionPolicy) isSafe(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var safe *safeError
+	if errors.As(err, &safe) {
+		return true
+	}
+	return rp.IsSafe != nil && rp.IsSafe(err)
+}
+
+// Safe marks the provided error as safe to expose to clients, so that problems
+// caused by it are never redacted by a RedactionPolicy.
+func Safe(err error) error {
+	return &safeError{err: err}
+}
+
+// A safeError wraps an error which is safe to expose to clients.
+type safeError struct {
+	err error
+}
+
+func (e *safeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error which was marked as safe.
+func (e *safeError) Unwrap() error {
+	return e.err
+}
+
+// asError returns p as an error, if it implements the error interface.
+func asError(p Details) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package problems
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactionPolicy_Redact(t *testing.T) {
+	errInternal := errors.New("pq: relation \"users\" does not exist")
+	errSafe := Safe(errors.New("username is already taken"))
+	errAllowed := errors.New("account is locked")
+
+	policy := &RedactionPolicy{
+		RedactServerErrors: true,
+		RedactErrorDetails: true,
+		Detail:             "An unexpected error occurred.",
+		IsSafe: func(err error) bool {
+			return errors.Is(err, errAllowed)
+		},
+	}
+
+	tests := []struct {
+		name         string
+		policy       *RedactionPolicy
+		problem      Details
+		expectDetail string
+	}{
+		{
+			name:         "should redact server errors",
+			policy:       policy,
+			problem:      NewDetailedProblem(http.StatusInternalServerError, "disk /var/lib/data is full"),
+			expectDetail: "An unexpected error occurred.",
+		},
+		{
+			name:         "should redact details taken from errors",
+			policy:       policy,
+			problem:      FromError(errInternal).WithStatus(http.StatusNotFound),
+			expectDetail: "An unexpected error occurred.",
+		},
+		{
+			name:   "should redact extended problems",
+			policy: policy,
+			problem: ExtFromError[creditProblemExt](errInternal).
+				WithStatus(http.StatusBadGateway).
+				WithExtension(creditProblemExt{Balance: 30}),
+			expectDetail: "An unexpected error occurred.",
+		},
+		{
+			name:         "should not redact client errors with explicit details",
+			policy:       policy,
+			problem:      NewDetailedProblem(http.StatusNotFound, "no such user"),
+			expectDetail: "no such user",
+		},
+		{
+			name:         "should not redact errors marked as safe",
+			policy:       policy,
+			problem:      FromError(fmt.Errorf("creating user: %w", errSafe)).WithStatus(http.StatusConflict),
+			expectDetail: "creating user: username is already taken",
+		},
+		{
+			name:         "should not redact errors allowed by the policy",
+			policy:       policy,
+			problem:      FromError(errAllowed).WithStatus(http.StatusServiceUnavailable),
+			expectDetail: "account is locked",
+		},
+		{
+			name:         "should remove details when no replacement is configured",
+			policy:       &RedactionPolicy{RedactServerErrors: true},
+			problem:      NewDetailedProblem(http.StatusInternalServerError, "disk /var/lib/data is full"),
+			expectDetail: "",
+		},
+		{
+			name:         "should not redact with a nil policy",
+			problem:      FromError(errInternal).WithStatus(http.StatusInternalServerError),
+			expectDetail: errInternal.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			redacted := test.policy.Redact(test.problem)
+
+			if detail := redacted.details().Detail; detail != test.expectDetail {
+				t.Errorf("expected detail %q, got %q", test.expectDetail, detail)
+			}
+
+			if redacted != test.problem {
+				if original, ok := test.problem.(error); ok && !errors.Is(asError(redacted), original) {
+					t.Errorf("expected the redacted problem to retain the original as its cause")
+				}
+				if test.problem.details().Detail == test.expectDetail {
+					t.Errorf("original problem was modified by redaction")
+				}
+			}
+		})
+	}
+}
+
+func TestWriter_redaction(t *testing.T) {
+	defer func(policy *RedactionPolicy) { DefaultRedactionPolicy = policy }(DefaultRedactionPolicy)
+	DefaultRedactionPolicy = &RedactionPolicy{RedactErrorDetails: true}
+
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return FromError(errors.New("open /etc/secrets: permission denied")).
+			WithStatus(http.StatusForbidden)
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if strings.Contains(rec.Body.String(), "/etc/secrets") {
+		t.Errorf("expected the error detail to be redacted, got %q", rec.Body.String())
+	}
+
+	writer := &Writer{Redaction: &RedactionPolicy{}}
+	rec = httptest.NewRecorder()
+	writer.Handle(handler)(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !strings.Contains(rec.Body.String(), "/etc/secrets") {
+		t.Errorf("expected the writer's policy to take precedence, got %q", rec.Body.String())
+	}
+}
+
+func TestProblemHandler_redaction(t *testing.T) {
+	defer func(policy *RedactionPolicy) { DefaultRedactionPolicy = policy }(DefaultRedactionPolicy)
+	DefaultRedactionPolicy = &RedactionPolicy{RedactErrorDetails: true}
+
+	problem := FromError(errors.New("pq: relation \"users\" does not exist")).
+		WithStatus(http.StatusInternalServerError)
+
+	handlers := map[string]http.HandlerFunc{
+		"ProblemHandler":    ProblemHandler(problem),
+		"XMLProblemHandler": XMLProblemHandler(problem),
+		"WriteJSONProblem": func(w http.ResponseWriter, r *http.Request) {
+			_ = WriteJSONProblem(w, problem)
+		},
+		"WriteXMLProblem": func(w http.ResponseWriter, r *http.Request) {
+			_ = WriteXMLProblem(w, problem)
+		},
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "pq:") {
+				t.Errorf("expected the error detail to be redacted, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriter_redactionRetainsMembers(t *testing.T) {
+	type quotaProblem struct {
+		Problem
+		Remaining int `json:"remaining"`
+	}
+
+	writer := &Writer{Redaction: &RedactionPolicy{RedactServerErrors: true, Detail: "redacted"}}
+	problem := &quotaProblem{
+		Problem:   *NewDetailedProblem(http.StatusServiceUnavailable, "quota service at 10.0.0.1 is down"),
+		Remaining: 3,
+	}
+
+	tests := []struct {
+		name    string
+		problem Details
+		expect  string
+	}{
+		{
+			name:    "should retain the fields of types embedding a problem",
+			problem: problem,
+			expect:  `{"type":"about:blank","title":"Service Unavailable","status":503,"detail":"redacted","remaining":3}`,
+		},
+		{
+			name: "should retain extension members",
+			problem: NewExt[creditProblemExt]().
+				WithStatus(http.StatusInternalServerError).
+				WithDetail("balance lookup failed").
+				WithExtension(creditProblemExt{Balance: 30}),
+			expect: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"redacted","balance":30,"accounts":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writer.Write(rec, httptest.NewRequest(http.MethodGet, "/", nil), test.problem)
+
+			if body := strings.TrimSpace(rec.Body.String()); body != test.expect {
+				t.Errorf("unexpected response:\ngot  %s\nwant %s", body, test.expect)
+			}
+		})
+	}
+
+	if problem.Detail != "quota service at 10.0.0.1 is down" {
+		t.Errorf("original problem was modified by redaction: %q", problem.Detail)
+	}
+}
+
+func TestWriter_redactionXML(t *testing.T) {
+	writer := &Writer{Redaction: &RedactionPolicy{RedactServerErrors: true}}
+	problem := NewExt[creditProblemExt]().
+		WithStatus(http.StatusInternalServerError).
+		WithDetail("balance lookup failed").
+		WithExtension(creditProblemExt{Balance: 30})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", ProblemMediaTypeXML)
+	rec := httptest.NewRecorder()
+	writer.Write(rec, req, problem)
+
+	expect := `<problem xmlns="urn:ietf:rfc:7807">` +
+		`<type>about:blank</type>` +
+		`<title>Internal Server Error</title>` +
+		`<status>500</status>` +
+		`<balance>30</balance>` +
+		`<accounts></accounts>` +
+		`</problem>`
+	if body := rec.Body.String(); body != expect {
+		t.Errorf("unexpected response:\ngot  %s\nwant %s", body, expect)
+	}
+}
@@ -0,0 +1,165 @@
+package problems
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A ProblemType describes a kind of problem which may be returned by a
+// service, so that every occurrence of the problem shares the same type URI,
+// title, and status code.
+type ProblemType struct {
+	// Name is a short identifier used to create problems of this type from a
+	// Registry, such as "out-of-credit". If empty, the URI is used instead.
+	Name string `json:"name,omitempty"`
+
+	// URI is the type URI of problems of this type.
+	URI string `json:"type"`
+
+	// Title is the canonical title of problems of this type.
+	Title string `json:"title"`
+
+	// Status is the default HTTP status code of problems of this type.
+	Status int `json:"status,omitempty"`
+
+	// Description is a human-readable explanation of the problem type, which
+	// should describe when the problem occurs and how it can be resolved.
+	Description string `json:"description,omitempty"`
+
+	// Extensions describes the extension members which problems of this type
+	// may contain.
+	Extensions []ExtensionMember `json:"extensions,omitempty"`
+
+	// Example is an example occurrence of the problem, which is included in
+	// the documentation served by Registry.Handler.
+	Example any `json:"example,omitempty"`
+}
+
+// An ExtensionMember describes a single extension member of a ProblemType.
+type ExtensionMember struct {
+	// Name is the name of the member as it appears in a problem.
+	Name string `json:"name"`
+
+	// Type is the JSON type of the member's value, such as "string" or
+	// "array".
+	Type string `json:"type,omitempty"`
+
+	// Description is a human-readable explanation of the member.
+	Description string `json:"description,omitempty"`
+}
+
+// New returns a new Problem with the type, title, and status of the
+// ProblemType.
+func (t ProblemType) New() *Problem {
+	return &Problem{
+		Type:   t.URI,
+		Title:  t.Title,
+		Status: t.Status,
+	}
+}
+
+// key returns the name which the ProblemType is registered under.
+func (t ProblemType) key() string {
+	if t.Name == "" {
+		return t.URI
+	}
+	return t.Name
+}
+
+// A Registry holds the set of problem types known to a service, so that each
+// type is defined exactly once. A Registry is safe for concurrent use.
+//
+// The zero value of Registry is an empty registry which is ready to use.
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]ProblemType
+	byURI  map[string]ProblemType
+	types  []ProblemType
+}
+
+// NewRegistry returns a new Registry containing the provided problem types. It
+// returns an error if any of the types could not be registered.
+func NewRegistry(types ...ProblemType) (*Registry, error) {
+	r := &Registry{}
+	for _, t := range types {
+		if err := r.Register(t); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds the provided problem type to the registry.
+//
+// An error is returned if the type has no title or an invalid URI, or if a
+// type with the same name or URI has already been registered.
+func (r *Registry) Register(t ProblemType) error {
+	if _, err := validate(t.URI, t.Title); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.byName == nil {
+		r.byName = make(map[string]ProblemType)
+		r.byURI = make(map[string]ProblemType)
+	}
+
+	if _, ok := r.byURI[t.URI]; ok {
+		return NewErrDuplicateProblemType(t.URI)
+	}
+	if _, ok := r.byName[t.key()]; ok {
+		return NewErrDuplicateProblemType(t.key())
+	}
+
+	r.byName[t.key()] = t
+	r.byURI[t.URI] = t
+	r.types = append(r.types, t)
+	return nil
+}
+
+// MustRegister behaves identically to Register, but panics if the problem
+// type could not be registered.
+func (r *Registry) MustRegister(t ProblemType) {
+	if err := r.Register(t); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the problem type registered with the provided name.
+func (r *Registry) Lookup(name string) (ProblemType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.byName[name]
+	return t, ok
+}
+
+// LookupURI returns the problem type registered with the provided type URI.
+func (r *Registry) LookupURI(uri string) (ProblemType, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.byURI[uri]
+	return t, ok
+}
+
+// Types returns every registered problem type, in the order in which they were
+// registered.
+func (r *Registry) Types() []ProblemType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]ProblemType(nil), r.types...)
+}
+
+// New returns a new Problem prefilled with the type URI, title, and default
+// status of the problem type registered with the provided name.
+//
+// New panics if no problem type has been registered with the name, as this
+// indicates a programming error.
+func (r *Registry) New(name string) *Problem {
+	t, ok := r.Lookup(name)
+	if !ok {
+		panic(fmt.Sprintf("%s: no problem type registered with name %q", errPrefix, name))
+	}
+	return t.New()
+}
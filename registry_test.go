@@ -0,0 +1,100 @@
+package problems
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var outOfCredit = ProblemType{
+	Name:        "out-of-credit",
+	URI:         "https://example.com/probs/out-of-credit",
+	Title:       "You do not have enough credit.",
+	Status:      http.StatusForbidden,
+	Description: "The account does not have a sufficient balance to complete the transaction.",
+	Extensions: []ExtensionMember{
+		{Name: "balance", Type: "number", Description: "The current balance of the account."},
+		{Name: "accounts", Type: "array", Description: "The accounts which may be used instead."},
+	},
+}
+
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistry(outOfCredit, ProblemType{
+		URI:   "https://example.com/probs/rate-limited",
+		Title: "Too many requests.",
+	})
+	if err != nil {
+		t.Fatalf("failed to create registry: %s", err)
+	}
+
+	problem := registry.New("out-of-credit").WithDetail("Your current balance is 30, but that costs 50.")
+	expect := &Problem{
+		Type:   "https://example.com/probs/out-of-credit",
+		Title:  "You do not have enough credit.",
+		Status: http.StatusForbidden,
+		Detail: "Your current balance is 30, but that costs 50.",
+	}
+	if !reflect.DeepEqual(problem, expect) {
+		t.Errorf("problems were not equal: wanted\n%#+v\n but got\n%#+v", expect, problem)
+	}
+
+	if _, ok := registry.Lookup("https://example.com/probs/rate-limited"); !ok {
+		t.Error("expected unnamed problem type to be registered by uri")
+	}
+
+	if typ, ok := registry.LookupURI(outOfCredit.URI); !ok || typ.Name != outOfCredit.Name {
+		t.Errorf("expected to find problem type by uri, got %#+v", typ)
+	}
+
+	if types := registry.Types(); len(types) != 2 || types[0].Name != outOfCredit.Name {
+		t.Errorf("expected types in registration order, got %#+v", types)
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name      string
+		problem   ProblemType
+		expectErr error
+	}{
+		{
+			name:      "should reject duplicate uris",
+			problem:   ProblemType{Name: "other", URI: outOfCredit.URI, Title: "Other"},
+			expectErr: &ErrDuplicateProblemType{Value: outOfCredit.URI},
+		},
+		{
+			name:      "should reject duplicate names",
+			problem:   ProblemType{Name: outOfCredit.Name, URI: "https://example.com/probs/other", Title: "Other"},
+			expectErr: &ErrDuplicateProblemType{Value: outOfCredit.Name},
+		},
+		{
+			name:      "should reject types without a title",
+			problem:   ProblemType{Name: "untitled", URI: "https://example.com/probs/untitled"},
+			expectErr: ErrTitleMustBeSet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var registry Registry
+			registry.MustRegister(outOfCredit)
+
+			err := registry.Register(test.problem)
+			if !reflect.DeepEqual(err, test.expectErr) && !errors.Is(err, test.expectErr) {
+				t.Errorf("expected error %v, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_New_unknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected New to panic for an unknown problem type")
+		}
+	}()
+
+	var registry Registry
+	registry.New("unknown")
+}
@@ -0,0 +1,162 @@
+package rpcstatus
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// A Code is a canonical error code, with the same values as the google.rpc.Code
+// enumeration used by gRPC.
+type Code uint32
+
+const (
+	// OK indicates that the operation completed successfully.
+	OK Code = 0
+
+	// Canceled indicates that the operation was cancelled, typically by the
+	// caller.
+	Canceled Code = 1
+
+	// Unknown indicates an unknown error, such as one which was returned by
+	// another address space and did not carry enough information.
+	Unknown Code = 2
+
+	// InvalidArgument indicates that the client specified an invalid
+	// argument, regardless of the state of the system.
+	InvalidArgument Code = 3
+
+	// DeadlineExceeded indicates that the deadline expired before the
+	// operation could complete.
+	DeadlineExceeded Code = 4
+
+	// NotFound indicates that a requested entity was not found.
+	NotFound Code = 5
+
+	// AlreadyExists indicates that the entity which a client attempted to
+	// create already exists.
+	AlreadyExists Code = 6
+
+	// PermissionDenied indicates that the caller does not have permission to
+	// execute the operation.
+	PermissionDenied Code = 7
+
+	// ResourceExhausted indicates that some resource has been exhausted, such
+	// as a per-user quota.
+	ResourceExhausted Code = 8
+
+	// FailedPrecondition indicates that the operation was rejected because
+	// the system is not in a state required for its execution.
+	FailedPrecondition Code = 9
+
+	// Aborted indicates that the operation was aborted, typically due to a
+	// concurrency issue such as a transaction abort.
+	Aborted Code = 10
+
+	// OutOfRange indicates that the operation was attempted past the valid
+	// range.
+	OutOfRange Code = 11
+
+	// Unimplemented indicates that the operation is not implemented or is not
+	// supported.
+	Unimplemented Code = 12
+
+	// Internal indicates that an invariant expected by the underlying system
+	// has been broken.
+	Internal Code = 13
+
+	// Unavailable indicates that the service is currently unavailable, and
+	// that the operation may be retried.
+	Unavailable Code = 14
+
+	// DataLoss indicates unrecoverable data loss or corruption.
+	DataLoss Code = 15
+
+	// Unauthenticated indicates that the request does not have valid
+	// authentication credentials for the operation.
+	Unauthenticated Code = 16
+)
+
+// codes describes each of the known codes, using the names and HTTP mapping
+// given by google.rpc.Code.
+var codes = map[Code]struct {
+	name   string
+	status int
+}{
+	OK:                 {name: "OK", status: http.StatusOK},
+	Canceled:           {name: "CANCELLED", status: 499},
+	Unknown:            {name: "UNKNOWN", status: http.StatusInternalServerError},
+	InvalidArgument:    {name: "INVALID_ARGUMENT", status: http.StatusBadRequest},
+	DeadlineExceeded:   {name: "DEADLINE_EXCEEDED", status: http.StatusGatewayTimeout},
+	NotFound:           {name: "NOT_FOUND", status: http.StatusNotFound},
+	AlreadyExists:      {name: "ALREADY_EXISTS", status: http.StatusConflict},
+	PermissionDenied:   {name: "PERMISSION_DENIED", status: http.StatusForbidden},
+	ResourceExhausted:  {name: "RESOURCE_EXHAUSTED", status: http.StatusTooManyRequests},
+	FailedPrecondition: {name: "FAILED_PRECONDITION", status: http.StatusBadRequest},
+	Aborted:            {name: "ABORTED", status: http.StatusConflict},
+	OutOfRange:         {name: "OUT_OF_RANGE", status: http.StatusBadRequest},
+	Unimplemented:      {name: "UNIMPLEMENTED", status: http.StatusNotImplemented},
+	Internal:           {name: "INTERNAL", status: http.StatusInternalServerError},
+	Unavailable:        {name: "UNAVAILABLE", status: http.StatusServiceUnavailable},
+	DataLoss:           {name: "DATA_LOSS", status: http.StatusInternalServerError},
+	Unauthenticated:    {name: "UNAUTHENTICATED", status: http.StatusUnauthorized},
+}
+
+// statuses maps HTTP status codes to the code which best describes them.
+// Where google.rpc.Code maps several codes to the same status, the most
+// general of them is used.
+var statuses = map[int]Code{
+	http.StatusBadRequest:                   InvalidArgument,
+	http.StatusUnauthorized:                 Unauthenticated,
+	http.StatusForbidden:                    PermissionDenied,
+	http.StatusNotFound:                     NotFound,
+	http.StatusConflict:                     Aborted,
+	http.StatusPreconditionFailed:           FailedPrecondition,
+	http.StatusRequestedRangeNotSatisfiable: OutOfRange,
+	http.StatusTooManyRequests:              ResourceExhausted,
+	499:                                     Canceled,
+	http.StatusInternalServerError:          Internal,
+	http.StatusNotImplemented:               Unimplemented,
+	http.StatusBadGateway:                   Unavailable,
+	http.StatusServiceUnavailable:           Unavailable,
+	http.StatusGatewayTimeout:               DeadlineExceeded,
+}
+
+// String returns the name of the code, as used by google.rpc.Code, such as
+// "NOT_FOUND".
+func (c Code) String() string {
+	if code, ok := codes[c]; ok {
+		return code.name
+	}
+	return "CODE(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// HTTPStatus returns the HTTP status code which corresponds to the code, as
+// documented by google.rpc.Code. Unknown codes correspond to 500 Internal
+// Server Error.
+func (c Code) HTTPStatus() int {
+	if code, ok := codes[c]; ok {
+		return code.status
+	}
+	return http.StatusInternalServerError
+}
+
+// FromHTTPStatus returns the code which corresponds to the provided HTTP
+// status code. Successful statuses correspond to OK, while any other status
+// without a corresponding code is mapped to FailedPrecondition for client
+// errors, Internal for server errors, and Unknown otherwise.
+func FromHTTPStatus(status int) Code {
+	if code, ok := statuses[status]; ok {
+		return code
+	}
+
+	switch {
+	case status >= 200 && status < 300:
+		return OK
+	case status >= 400 && status < 500:
+		return FailedPrecondition
+	case status >= 500 && status < 600:
+		return Internal
+	default:
+		return Unknown
+	}
+}
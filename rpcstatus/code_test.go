@@ -0,0 +1,70 @@
+package rpcstatus
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCode_HTTPStatus(t *testing.T) {
+	tests := []struct {
+		code   Code
+		name   string
+		status int
+	}{
+		{code: OK, name: "OK", status: http.StatusOK},
+		{code: Canceled, name: "CANCELLED", status: 499},
+		{code: InvalidArgument, name: "INVALID_ARGUMENT", status: http.StatusBadRequest},
+		{code: DeadlineExceeded, name: "DEADLINE_EXCEEDED", status: http.StatusGatewayTimeout},
+		{code: NotFound, name: "NOT_FOUND", status: http.StatusNotFound},
+		{code: AlreadyExists, name: "ALREADY_EXISTS", status: http.StatusConflict},
+		{code: PermissionDenied, name: "PERMISSION_DENIED", status: http.StatusForbidden},
+		{code: ResourceExhausted, name: "RESOURCE_EXHAUSTED", status: http.StatusTooManyRequests},
+		{code: FailedPrecondition, name: "FAILED_PRECONDITION", status: http.StatusBadRequest},
+		{code: Unimplemented, name: "UNIMPLEMENTED", status: http.StatusNotImplemented},
+		{code: Unavailable, name: "UNAVAILABLE", status: http.StatusServiceUnavailable},
+		{code: DataLoss, name: "DATA_LOSS", status: http.StatusInternalServerError},
+		{code: Unauthenticated, name: "UNAUTHENTICATED", status: http.StatusUnauthorized},
+		{code: Code(42), name: "CODE(42)", status: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if name := test.code.String(); name != test.name {
+				t.Errorf("expected name %q, got %q", test.name, name)
+			}
+			if status := test.code.HTTPStatus(); status != test.status {
+				t.Errorf("expected status %d, got %d", test.status, status)
+			}
+		})
+	}
+}
+
+func TestFromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		expect Code
+	}{
+		{status: http.StatusOK, expect: OK},
+		{status: http.StatusNoContent, expect: OK},
+		{status: http.StatusBadRequest, expect: InvalidArgument},
+		{status: http.StatusUnauthorized, expect: Unauthenticated},
+		{status: http.StatusForbidden, expect: PermissionDenied},
+		{status: http.StatusNotFound, expect: NotFound},
+		{status: http.StatusConflict, expect: Aborted},
+		{status: http.StatusTooManyRequests, expect: ResourceExhausted},
+		{status: 499, expect: Canceled},
+		{status: http.StatusUnsupportedMediaType, expect: FailedPrecondition},
+		{status: http.StatusInternalServerError, expect: Internal},
+		{status: http.StatusNotImplemented, expect: Unimplemented},
+		{status: http.StatusServiceUnavailable, expect: Unavailable},
+		{status: http.StatusGatewayTimeout, expect: DeadlineExceeded},
+		{status: http.StatusHTTPVersionNotSupported, expect: Internal},
+		{status: 0, expect: Unknown},
+	}
+
+	for _, test := range tests {
+		if code := FromHTTPStatus(test.status); code != test.expect {
+			t.Errorf("expected status %d to map to %s, got %s", test.status, test.expect, code)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package rpcstatus converts problems to and from the canonical error model
+// used by gRPC and other RPC systems, described by google.rpc.Status, without
+// depending on gRPC or protobuf.
+//
+// A problem is converted into a Status whose Code corresponds to the problem's
+// HTTP status, as documented by google.rpc.Code, and whose message is the
+// problem's detail. Every member of the problem, including its type and any
+// extension members, is also carried as a structured detail of the Status so
+// that the problem can be restored without loss.
+package rpcstatus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/moogar0880/problems"
+)
+
+// ProblemDetailsType is the type URL which identifies the detail used to carry
+// the members of a problem within a Status.
+const ProblemDetailsType = "github.com/moogar0880/problems/problems.ProblemDetails"
+
+// typeMember is the member which holds the type URL of each detail, in the
+// same way as the JSON representation of a google.protobuf.Any.
+const typeMember = "@type"
+
+// A Status is the equivalent of a google.rpc.Status message, and is serialized
+// as JSON in the same way as the protobuf JSON representation of one.
+type Status struct {
+	// Code is the canonical error code of the status.
+	Code Code `json:"code"`
+
+	// Message is a developer-facing error message.
+	Message string `json:"message,omitempty"`
+
+	// Details contains structured details about the error. Each detail is a
+	// JSON object whose "@type" member contains a type URL identifying its
+	// contents, in the same way as the JSON representation of a
+	// google.protobuf.Any.
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// FromProblem converts the provided problem into a Status.
+//
+// The code of the Status corresponds to the status of the problem, and its
+// message is the problem's detail, or title if it has no detail. The problem
+// itself is added as a ProblemDetailsType detail, using the same members as
+// its JSON representation.
+func FromProblem(p problems.Details) (*Status, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var members struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	message := members.Detail
+	if message == "" {
+		message = members.Title
+	}
+
+	detail, err := withType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{
+		Code:    FromHTTPStatus(members.Status),
+		Message: message,
+		Details: []json.RawMessage{detail},
+	}, nil
+}
+
+// Decode decodes the problem carried by the status into v, which may be any
+// type that can be decoded from the JSON representation of a problem, such as
+// a *problems.Problem or *problems.ExtendedProblem.
+//
+// If the status has no ProblemDetailsType detail, such as when it was not
+// created by FromProblem, then a problem is created from its code and message.
+func (s *Status) Decode(v any) error {
+	for _, detail := range s.Details {
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(detail, &members); err != nil {
+			return err
+		}
+
+		var typ string
+		if err := json.Unmarshal(members[typeMember], &typ); err != nil || typ != ProblemDetailsType {
+			continue
+		}
+		delete(members, typeMember)
+
+		data, err := json.Marshal(members)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, v)
+	}
+
+	data, err := json.Marshal(problems.NewDetailedProblem(s.Code.HTTPStatus(), s.Message))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Problem returns the problem carried by the status, as decoded by Decode. If
+// the problem cannot be decoded then one is created from the status's code and
+// message instead.
+func (s *Status) Problem() *problems.Problem {
+	p := problems.New()
+	if err := s.Decode(p); err != nil {
+		return problems.NewDetailedProblem(s.Code.HTTPStatus(), s.Message)
+	}
+	return p
+}
+
+// Error implements the error interface, and formats the status in the same
+// way as gRPC.
+func (s *Status) Error() string {
+	return fmt.Sprintf("rpc error: code = %s desc = %s", s.Code, s.Message)
+}
+
+// withType adds the "@type" member, identifying the detail as a problem, to
+// the start of the provided JSON object.
+func withType(data []byte) (json.RawMessage, error) {
+	typ, err := json.Marshal(map[string]string{typeMember: ProblemDetailsType})
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("{}")) {
+		return typ, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(typ, []byte("}")))
+	buf.WriteByte(',')
+	buf.Write(bytes.TrimPrefix(data, []byte("{")))
+	return buf.Bytes(), nil
+}
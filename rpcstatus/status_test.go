@@ -0,0 +1,117 @@
+package rpcstatus
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/moogar0880/problems"
+)
+
+type creditProblemExt struct {
+	Balance  float64  `json:"balance"`
+	Accounts []string `json:"accounts"`
+}
+
+func TestFromProblem(t *testing.T) {
+	problem := problems.NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusTooManyRequests).
+		WithDetail("Your current balance is 30, but that costs 50.").
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}})
+
+	status, err := FromProblem(problem)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expect := `{"code":8,"message":"Your current balance is 30, but that costs 50.","details":[{"@type":"github.com/moogar0880/problems/problems.ProblemDetails","type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":429,"detail":"Your current balance is 30, but that costs 50.","balance":30,"accounts":["/account/12345"]}]}`
+	if string(data) != expect {
+		t.Errorf("unexpected status:\ngot\n%s\nwant\n%s", data, expect)
+	}
+
+	if msg := status.Error(); msg != "rpc error: code = RESOURCE_EXHAUSTED desc = Your current balance is 30, but that costs 50." {
+		t.Errorf("unexpected error message: %q", msg)
+	}
+
+	var decoded problems.ExtendedProblem[creditProblemExt]
+	if err := status.Decode(&decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(&decoded, problem) {
+		t.Errorf("problems were not equal: wanted\n%+v\nbut got\n%+v", problem, &decoded)
+	}
+}
+
+func TestFromProblem_Message(t *testing.T) {
+	status, err := FromProblem(problems.NewStatusProblem(http.StatusNotFound))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if status.Code != NotFound || status.Message != "Not Found" {
+		t.Errorf("expected NOT_FOUND with the problem's title, got %s", status)
+	}
+
+	if problem := status.Problem(); !reflect.DeepEqual(problem, problems.NewStatusProblem(http.StatusNotFound)) {
+		t.Errorf("expected the problem to be restored, got %+v", problem)
+	}
+}
+
+func TestFromProblem_Error(t *testing.T) {
+	_, err := FromProblem(problems.NewExt[[]string]().WithExtension([]string{"a"}))
+	if !errors.Is(err, problems.ErrExtensionsMustBeObject) {
+		t.Errorf("expected ErrExtensionsMustBeObject, got %v", err)
+	}
+}
+
+func TestStatus_Problem(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *Status
+		expect *problems.Problem
+	}{
+		{
+			name:   "should create problems from the code and message",
+			status: &Status{Code: Unavailable, Message: "try again later"},
+			expect: problems.NewDetailedProblem(http.StatusServiceUnavailable, "try again later"),
+		},
+		{
+			name: "should ignore other details",
+			status: &Status{
+				Code:    NotFound,
+				Message: "no such user",
+				Details: []json.RawMessage{
+					json.RawMessage(`{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"USER_NOT_FOUND"}`),
+					json.RawMessage(`{"@type":"github.com/moogar0880/problems/problems.ProblemDetails","type":"https://example.com/probs/no-user","title":"No such user","status":404}`),
+				},
+			},
+			expect: problems.New().WithType("https://example.com/probs/no-user").WithTitle("No such user").WithStatus(http.StatusNotFound),
+		},
+		{
+			name: "should fall back to the code and message for malformed details",
+			status: &Status{
+				Code:    Internal,
+				Message: "oops",
+				Details: []json.RawMessage{json.RawMessage(`[]`)},
+			},
+			expect: problems.NewDetailedProblem(http.StatusInternalServerError, "oops"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if problem := test.status.Problem(); !reflect.DeepEqual(problem, test.expect) {
+				t.Errorf("problems were not equal: wanted\n%+v\nbut got\n%+v", test.expect, problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package problems
+
+import (
+	"reflect"
+	"time"
+)
+
+// A Template is an immutable problem which can safely be shared between
+// goroutines, such as a package-level variable describing a common problem.
+//
+// Each of the builder methods on Template returns a new *Problem, leaving the
+// Template itself unchanged. A Template can also be used as the target of
+// errors.Is, to check whether an error is a problem of the same kind.
+type Template struct {
+	problem Problem
+}
+
+// NewTemplate returns a new Template from a copy of the provided problem.
+func NewTemplate(p *Problem) Template {
+	return Template{problem: *p.Clone()}
+}
+
+// NewStatusTemplate returns a new Template for the provided HTTP status code.
+// See NewStatusProblem for more information.
+func NewStatusTemplate(status int) Template {
+	return NewTemplate(NewStatusProblem(status))
+}
+
+// New returns a new Problem which is a copy of the Template.
+func (t Template) New() *Problem {
+	return t.problem.Clone()
+}
+
+// Error implements the error interface, allowing a Template to be used as the
+// target of errors.Is to match problems created from it.
+func (t Template) Error() string {
+	return t.problem.Error()
+}
+
+// details implements the Details interface.
+func (t Template) details() *Problem {
+	return t.problem.Clone()
+}
+
+// WithType returns a new Problem with the type field set to the provided
+// string.
+func (t Template) WithType(typ string) *Problem {
+	return t.New().WithType(typ)
+}
+
+// WithTitle returns a new Problem with the title field set to the provided
+// string.
+func (t Template) WithTitle(title string) *Problem {
+	return t.New().WithTitle(title)
+}
+
+// WithStatus returns a new Problem with the status field set to the provided
+// int.
+func (t Template) WithStatus(status int) *Problem {
+	return t.New().WithStatus(status)
+}
+
+// WithDetail returns a new Problem with the detail message set to the provided
+// string.
+func (t Template) WithDetail(detail string) *Problem {
+	return t.New().WithDetail(detail)
+}
+
+// WithDetailf returns a new Problem with the detail message set to the
+// provided format string, formatted with the provided arguments.
+func (t Template) WithDetailf(format string, args ...interface{}) *Problem {
+	return t.New().WithDetailf(format, args...)
+}
+
+// WithError returns a new Problem with the detail message set to the provided
+// error, and the error retained as its cause.
+func (t Template) WithError(err error) *Problem {
+	return t.New().WithError(err)
+}
+
+// WithCause returns a new Problem with its cause set to the provided error.
+func (t Template) WithCause(err error) *Problem {
+	return t.New().WithCause(err)
+}
+
+// WithInstance returns a new Problem with the instance uri set to the provided
+// string.
+func (t Template) WithInstance(instance string) *Problem {
+	return t.New().WithInstance(instance)
+}
+
+// WithHeader returns a new Problem with the named header field set to the
+// provided values.
+func (t Template) WithHeader(name string, values ...string) *Problem {
+	return t.New().WithHeader(name, values...)
+}
+
+// WithRetryAfter returns a new Problem with the Retry-After header field set
+// to the provided delay.
+func (t Template) WithRetryAfter(d time.Duration) *Problem {
+	return t.New().WithRetryAfter(d)
+}
+
+// An ExtTemplate is an immutable extended problem which can safely be shared
+// between goroutines.
+//
+// Each of the builder methods on ExtTemplate returns a new *ExtendedProblem,
+// with a deep copy of the template's extensions, leaving the ExtTemplate
+// itself unchanged.
+type ExtTemplate[T any] struct {
+	problem *ExtendedProblem[T]
+}
+
+// NewExtTemplate returns a new ExtTemplate from a deep copy of the provided
+// problem.
+func NewExtTemplate[T any](p *ExtendedProblem[T]) ExtTemplate[T] {
+	return ExtTemplate[T]{problem: p.Clone()}
+}
+
+// New returns a new ExtendedProblem which is a deep copy of the ExtTemplate.
+func (t ExtTemplate[T]) New() *ExtendedProblem[T] {
+	return t.problem.Clone()
+}
+
+// Error implements the error interface, allowing an ExtTemplate to be used as
+// the target of errors.Is to match problems created from it.
+func (t ExtTemplate[T]) Error() string {
+	return t.problem.Error()
+}
+
+// details implements the Details interface.
+func (t ExtTemplate[T]) details() *Problem {
+	return t.problem.Problem.Clone()
+}
+
+// WithType returns a new ExtendedProblem with the type field set to the
+// provided string.
+func (t ExtTemplate[T]) WithType(typ string) *ExtendedProblem[T] {
+	return t.New().WithType(typ)
+}
+
+// WithTitle returns a new ExtendedProblem with the title field set to the
+// provided string.
+func (t ExtTemplate[T]) WithTitle(title string) *ExtendedProblem[T] {
+	return t.New().WithTitle(title)
+}
+
+// WithStatus returns a new ExtendedProblem with the status field set to the
+// provided int.
+func (t ExtTemplate[T]) WithStatus(status int) *ExtendedProblem[T] {
+	return t.New().WithStatus(status)
+}
+
+// WithDetail returns a new ExtendedProblem with the detail message set to the
+// provided string.
+func (t ExtTemplate[T]) WithDetail(detail string) *ExtendedProblem[T] {
+	return t.New().WithDetail(detail)
+}
+
+// WithDetailf returns a new ExtendedProblem with the detail message set to the
+// provided format string, formatted with the provided arguments.
+func (t ExtTemplate[T]) WithDetailf(format string, args ...interface{}) *ExtendedProblem[T] {
+	return t.New().WithDetailf(format, args...)
+}
+
+// WithError returns a new ExtendedProblem with the detail message set to the
+// provided error, and the error retained as its cause.
+func (t ExtTemplate[T]) WithError(err error) *ExtendedProblem[T] {
+	return t.New().WithError(err)
+}
+
+// WithCause returns a new ExtendedProblem with its cause set to the provided
+// error.
+func (t ExtTemplate[T]) WithCause(err error) *ExtendedProblem[T] {
+	return t.New().WithCause(err)
+}
+
+// WithInstance returns a new ExtendedProblem with the instance uri set to the
+// provided string.
+func (t ExtTemplate[T]) WithInstance(instance string) *ExtendedProblem[T] {
+	return t.New().WithInstance(instance)
+}
+
+// WithHeader returns a new ExtendedProblem with the named header field set to
+// the provided values.
+func (t ExtTemplate[T]) WithHeader(name string, values ...string) *ExtendedProblem[T] {
+	return t.New().WithHeader(name, values...)
+}
+
+// WithRetryAfter returns a new ExtendedProblem with the Retry-After header
+// field set to the provided delay.
+func (t ExtTemplate[T]) WithRetryAfter(d time.Duration) *ExtendedProblem[T] {
+	return t.New().WithRetryAfter(d)
+}
+
+// WithExtension returns a new ExtendedProblem with the extensions value set to
+// the provided extension of type T.
+func (t ExtTemplate[T]) WithExtension(ext T) *ExtendedProblem[T] {
+	return t.New().WithExtension(ext)
+}
+
+// deepCopy returns a deep copy of v. See ExtendedProblem.Clone for the
+// limitations of the copy.
+func deepCopy[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	dst.Set(copyValue(src))
+
+	// When T is an interface type and v is nil, the assertion fails and the
+	// zero value of T, which is also nil, is returned.
+	c, _ := dst.Interface().(T)
+	return c
+}
+
+// copyValue returns a deep copy of the provided reflect.Value.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(copyValue(v.Elem()))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(copyValue(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,111 @@
+package problems
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestProblem_Clone(t *testing.T) {
+	original := NewDetailedProblem(http.StatusNotFound, "original")
+	clone := original.Clone().WithDetail("clone")
+
+	if original.Detail != "original" {
+		t.Errorf("modifying a clone modified the original problem: %q", original.Detail)
+	}
+
+	if clone.Title != original.Title || clone.Status != original.Status {
+		t.Errorf("clone does not match the original problem: %#+v", clone)
+	}
+}
+
+func TestExtendedProblem_Clone(t *testing.T) {
+	type nested struct {
+		Values map[string][]int  `json:"values"`
+		Ptr    *creditProblemExt `json:"ptr"`
+		Any    any               `json:"any"`
+		Array  [2][]string       `json:"array"`
+	}
+
+	original := NewExt[nested]().
+		WithStatus(http.StatusForbidden).
+		WithExtension(nested{
+			Values: map[string][]int{"a": {1, 2}},
+			Ptr:    &creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}},
+			Any:    map[string]any{"b": []any{"c"}},
+			Array:  [2][]string{{"d"}, nil},
+		})
+	snapshot := original.Clone()
+
+	clone := original.Clone()
+	clone.Extensions.Values["a"][0] = 100
+	clone.Extensions.Values["new"] = []int{1}
+	clone.Extensions.Ptr.Balance = 0
+	clone.Extensions.Ptr.Accounts[0] = "/account/00000"
+	clone.Extensions.Any.(map[string]any)["b"].([]any)[0] = "modified"
+	clone.Extensions.Array[0][0] = "modified"
+
+	if !reflect.DeepEqual(original, snapshot) {
+		t.Errorf("modifying a clone modified the original problem:\ngot\n%#+v\nwant\n%#+v", original.Extensions, snapshot.Extensions)
+	}
+}
+
+func TestExtendedProblem_Clone_nilInterface(t *testing.T) {
+	if clone := NewExt[any]().Clone(); clone.Extensions != nil {
+		t.Errorf("expected nil extensions, got %#v", clone.Extensions)
+	}
+
+	if clone := NewExt[error]().Clone(); clone.Extensions != nil {
+		t.Errorf("expected nil extensions, got %#v", clone.Extensions)
+	}
+
+	if problem := NewExtTemplate(NewExt[any]()).New(); problem.Extensions != nil {
+		t.Errorf("expected nil extensions, got %#v", problem.Extensions)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	notFound := NewStatusTemplate(http.StatusNotFound)
+
+	problem := notFound.WithDetail("no such user").WithInstance("/users/1")
+	if problem.Status != http.StatusNotFound || problem.Detail != "no such user" {
+		t.Errorf("template did not produce the expected problem: %#+v", problem)
+	}
+
+	if original := notFound.New(); original.Detail != "" || original.Instance != "" {
+		t.Errorf("template was modified by its builder methods: %#+v", original)
+	}
+}
+
+func TestTemplate_concurrentUse(t *testing.T) {
+	notFound := NewStatusTemplate(http.StatusNotFound)
+	credit := NewExtTemplate(NewExt[creditProblemExt]().
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			detail := fmt.Sprintf("request %d", i)
+			problem := notFound.WithDetail(detail)
+			ext := credit.WithDetail(detail)
+			ext.Extensions.Accounts[0] = detail
+			ext.Extensions.Balance = float64(i)
+
+			if problem.Detail != detail || ext.Detail != detail {
+				t.Errorf("concurrent requests overwrote each other's details")
+			}
+			_ = ext.Error()
+		}(i)
+	}
+	wg.Wait()
+
+	if p := credit.New(); p.Extensions.Accounts[0] != "/account/12345" || p.Extensions.Balance != 30 {
+		t.Errorf("template extensions were modified: %#+v", p.Extensions)
+	}
+}
@@ -2,9 +2,21 @@ package problems
 
 import (
 	"encoding/json"
+	"net/http"
 	"net/url"
 )
 
+// Validated is implemented only by the sealed problem types, ValidProblem and
+// ValidExtendedProblem, and by types which embed them. It allows functions to
+// require, at compile time, that the problems they are given have been
+// validated.
+type Validated interface {
+	Details
+
+	// validated seals the interface to the validated problem types.
+	validated()
+}
+
 // A ValidProblem is a sealed variant of Problem which is guaranteed to have
 // valid fields.
 //
@@ -30,6 +42,11 @@ type ValidProblem struct {
 	// A URI that identifies the specific occurrence of the problem. This URI
 	// may or may not yield further information if de-referenced.
 	instance string
+
+	// header contains the header fields which are written along with the
+	// problem by the HTTP handlers. It is shared with the Problem which was
+	// validated, as a Problem never modifies its header in place.
+	header *http.Header
 }
 
 // Validate validates the content of the Problem instance. If the Problem is
@@ -44,13 +61,19 @@ func (p *Problem) Validate() (*ValidProblem, error) {
 		return nil, err
 	}
 
+	return newValidProblem(p, typ), nil
+}
+
+// newValidProblem seals the provided, already validated, Problem.
+func newValidProblem(p *Problem, typ *url.URL) *ValidProblem {
 	return &ValidProblem{
 		typ:      typ,
 		title:    p.Title,
 		status:   p.Status,
 		detail:   p.Detail,
 		instance: p.Instance,
-	}, nil
+		header:   p.header,
+	}
 }
 
 // IntoProblem allows you to convert from a ValidProblem back into a Problem.
@@ -61,15 +84,78 @@ func (p *ValidProblem) IntoProblem() *Problem {
 		Status:   p.status,
 		Detail:   p.detail,
 		Instance: p.instance,
+		header:   p.header,
 	}
 }
 
+// Type returns the URI which identifies the problem type.
+func (p *ValidProblem) Type() *url.URL {
+	typ := *p.typ
+	return &typ
+}
+
+// Title returns the short, human-readable, summary of the problem type.
+func (p *ValidProblem) Title() string {
+	return p.title
+}
+
+// Status returns the HTTP status code for this occurrence of the problem.
+func (p *ValidProblem) Status() int {
+	return p.status
+}
+
+// Detail returns the human-readable explanation specific to this occurrence
+// of the problem.
+func (p *ValidProblem) Detail() string {
+	return p.detail
+}
+
+// Instance returns the URI which identifies this occurrence of the problem.
+func (p *ValidProblem) Instance() string {
+	return p.instance
+}
+
+// Header returns a copy of the header fields which are written along with the
+// problem by the HTTP handlers, or nil if there are none.
+func (p *ValidProblem) Header() http.Header {
+	if p.header == nil {
+		return nil
+	}
+	return p.header.Clone()
+}
+
+// details implements the Details interface.
+func (p *ValidProblem) details() *Problem {
+	return p.IntoProblem()
+}
+
+// validated implements the Validated interface.
+func (p *ValidProblem) validated() {}
+
 // MarshalJSON implements the json.Marshaler interface and ensures that a
 // ValidProblem is properly serialized into JSON.
 func (p *ValidProblem) MarshalJSON() ([]byte, error) {
 	return json.Marshal(p.IntoProblem())
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface. The decoded problem
+// is validated in the same way as by Problem.Validate, and the validation error
+// is returned if it is invalid.
+func (p *ValidProblem) UnmarshalJSON(data []byte) error {
+	var problem Problem
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return err
+	}
+
+	valid, err := problem.Validate()
+	if err != nil {
+		return err
+	}
+
+	*p = *valid
+	return nil
+}
+
 // A ValidExtendedProblem is a sealed variant of ExtendedProblem which is
 // guaranteed to contain valid fields.
 //
@@ -97,28 +183,28 @@ func (p *ExtendedProblem[T]) Validate() (*ValidExtendedProblem[T], error) {
 		return nil, err
 	}
 
+	return newValidExtendedProblem(p, typ), nil
+}
+
+// newValidExtendedProblem seals the provided, already validated,
+// ExtendedProblem.
+func newValidExtendedProblem[T any](p *ExtendedProblem[T], typ *url.URL) *ValidExtendedProblem[T] {
 	return &ValidExtendedProblem[T]{
-		ValidProblem: ValidProblem{
-			typ:      typ,
-			title:    p.Title,
-			status:   p.Status,
-			detail:   p.Detail,
-			instance: p.Instance,
-		},
-		extensions: p.Extensions,
-	}, nil
+		ValidProblem: *newValidProblem(&p.Problem, typ),
+		extensions:   p.Extensions,
+	}
 }
 
 // IntoProblem allows you to convert from a ValidExtendedProblem back into a
 // Problem.
 func (p *ValidExtendedProblem[T]) IntoProblem() *Problem {
-	return &Problem{
-		Type:     p.typ.String(),
-		Title:    p.title,
-		Status:   p.status,
-		Detail:   p.detail,
-		Instance: p.instance,
-	}
+	return p.ValidProblem.IntoProblem()
+}
+
+// Extensions returns a deep copy of the problem's extensions. See
+// ExtendedProblem.Clone for the limitations of the copy.
+func (p *ValidExtendedProblem[T]) Extensions() T {
+	return deepCopy(p.extensions)
 }
 
 // IntoExtendedProblem allows you to convert from a ValidExtendedProblem back
@@ -136,6 +222,24 @@ func (p *ValidExtendedProblem[T]) MarshalJSON() ([]byte, error) {
 	return json.Marshal(p.IntoExtendedProblem())
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface. The decoded problem
+// is validated in the same way as by ExtendedProblem.Validate, and the
+// validation error is returned if it is invalid.
+func (p *ValidExtendedProblem[T]) UnmarshalJSON(data []byte) error {
+	var problem ExtendedProblem[T]
+	if err := json.Unmarshal(data, &problem); err != nil {
+		return err
+	}
+
+	valid, err := problem.Validate()
+	if err != nil {
+		return err
+	}
+
+	*p = *valid
+	return nil
+}
+
 func validate(typ, title string) (*url.URL, error) {
 	if len(title) == 0 {
 		return nil, ErrTitleMustBeSet
@@ -3,6 +3,8 @@ package problems
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"net/http"
 	"testing"
 )
@@ -140,3 +142,160 @@ func TestValidProblem_MarshalJSON(t *testing.T) {
 		t.Errorf("problem does not match validated")
 	}
 }
+
+func TestValidProblem_Accessors(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusForbidden).
+		WithDetail("Your current balance is 30, but that costs 50.").
+		WithInstance("/account/12345/msgs/abc").
+		WithExtension(creditProblemExt{Balance: 30, Accounts: []string{"/account/12345"}})
+
+	valid, err := problem.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+
+	if typ := valid.Type(); typ.Host != "example.com" || typ.String() != problem.Type {
+		t.Errorf("expected type %q, got %q", problem.Type, typ)
+	}
+
+	if valid.Title() != problem.Title {
+		t.Errorf("expected title %q, got %q", problem.Title, valid.Title())
+	}
+
+	if valid.Status() != problem.Status {
+		t.Errorf("expected status %d, got %d", problem.Status, valid.Status())
+	}
+
+	if valid.Detail() != problem.Detail {
+		t.Errorf("expected detail %q, got %q", problem.Detail, valid.Detail())
+	}
+
+	if valid.Instance() != problem.Instance {
+		t.Errorf("expected instance %q, got %q", problem.Instance, valid.Instance())
+	}
+
+	valid.Type().Path = "/modified"
+	ext := valid.Extensions()
+	ext.Accounts[0] = "/modified"
+
+	if valid.Type().Path == "/modified" || valid.Extensions().Accounts[0] == "/modified" {
+		t.Errorf("valid problem was modified through its accessors")
+	}
+}
+
+func TestValidProblem_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expectErr error
+	}{
+		{
+			name: "should decode valid problems",
+			data: `{"type":"https://example.com/probs/out-of-credit","title":"You do not have enough credit.","status":403,"balance":30}`,
+		},
+		{
+			name:      "should reject problems without a title",
+			data:      `{"type":"https://example.com/probs/out-of-credit","status":403}`,
+			expectErr: ErrTitleMustBeSet,
+		},
+		{
+			name:      "should reject problems with an invalid type",
+			data:      `{"type":"::/","title":"Invalid","status":403}`,
+			expectErr: &ErrInvalidProblemType{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var valid ValidProblem
+			validErr := json.Unmarshal([]byte(test.data), &valid)
+
+			var validExt ValidExtendedProblem[creditProblemExt]
+			validExtErr := json.Unmarshal([]byte(test.data), &validExt)
+
+			for _, err := range []error{validErr, validExtErr} {
+				checkValidationError(t, err, test.expectErr)
+			}
+
+			if test.expectErr == nil {
+				if valid.Title() != "You do not have enough credit." || valid.Status() != http.StatusForbidden {
+					t.Errorf("valid problem was not decoded: %#+v", valid)
+				}
+
+				if validExt.Extensions().Balance != 30 {
+					t.Errorf("valid extended problem extensions were not decoded: %#+v", validExt)
+				}
+			}
+		})
+	}
+}
+
+func TestValidProblem_UnmarshalXML(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expectErr error
+	}{
+		{
+			name: "should decode valid problems",
+			data: `<problem xmlns="urn:ietf:rfc:7807"><type>https://example.com/probs/out-of-credit</type><title>You do not have enough credit.</title><status>403</status><balance>30</balance></problem>`,
+		},
+		{
+			name:      "should reject problems without a title",
+			data:      `<problem xmlns="urn:ietf:rfc:7807"><type>https://example.com/probs/out-of-credit</type></problem>`,
+			expectErr: ErrTitleMustBeSet,
+		},
+		{
+			name:      "should reject problems with an invalid type",
+			data:      `<problem xmlns="urn:ietf:rfc:7807"><type>::/</type><title>Invalid</title></problem>`,
+			expectErr: &ErrInvalidProblemType{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var valid ValidProblem
+			validErr := xml.Unmarshal([]byte(test.data), &valid)
+
+			var validExt ValidExtendedProblem[creditProblemExt]
+			validExtErr := xml.Unmarshal([]byte(test.data), &validExt)
+
+			for _, err := range []error{validErr, validExtErr} {
+				checkValidationError(t, err, test.expectErr)
+			}
+
+			if test.expectErr == nil {
+				if valid.Title() != "You do not have enough credit." || valid.Status() != http.StatusForbidden {
+					t.Errorf("valid problem was not decoded: %#+v", valid)
+				}
+
+				if validExt.Extensions().Balance != 30 {
+					t.Errorf("valid extended problem extensions were not decoded: %#+v", validExt)
+				}
+			}
+		})
+	}
+}
+
+// checkValidationError verifies that err matches the expected validation
+// error, or is nil if no error is expected.
+func checkValidationError(t *testing.T, err, expect error) {
+	t.Helper()
+
+	var invalidType *ErrInvalidProblemType
+	switch {
+	case expect == nil:
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	case errors.As(expect, &invalidType):
+		if !errors.As(err, &invalidType) {
+			t.Errorf("expected ErrInvalidProblemType, got %v", err)
+		}
+	case !errors.Is(err, expect):
+		t.Errorf("expected %v, got %v", expect, err)
+	}
+}
@@ -0,0 +1,235 @@
+package problems
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// A ValidationError describes a single reason that a request is invalid, and
+// the location within the request which caused it.
+//
+// At most one of Pointer, Parameter, or Header should be set.
+type ValidationError struct {
+	// Detail is a human-readable explanation of the error.
+	Detail string `json:"detail" xml:"detail"`
+
+	// Pointer is a JSON Pointer (RFC-6901), in its URI fragment identifier
+	// representation, to the member of the request body which is invalid.
+	// See Pointer for a helper which builds one.
+	Pointer string `json:"pointer,omitempty" xml:"pointer,omitempty"`
+
+	// Parameter is the name of the invalid query parameter.
+	Parameter string `json:"parameter,omitempty" xml:"parameter,omitempty"`
+
+	// Header is the name of the invalid request header.
+	Header string `json:"header,omitempty" xml:"header,omitempty"`
+
+	// Code is an optional, machine-readable, code identifying the error.
+	Code string `json:"code,omitempty" xml:"code,omitempty"`
+}
+
+// Error implements the error interface and allows a ValidationError to be
+// returned from validation functions, and later collected with
+// FromValidationErrors.
+func (e ValidationError) Error() string {
+	switch {
+	case e.Pointer != "":
+		return fmt.Sprintf("%s: %s", e.Pointer, e.Detail)
+	case e.Parameter != "":
+		return fmt.Sprintf("query parameter %q: %s", e.Parameter, e.Detail)
+	case e.Header != "":
+		return fmt.Sprintf("header %q: %s", e.Header, e.Detail)
+	default:
+		return e.Detail
+	}
+}
+
+// pointerEscaper escapes the reference tokens of a JSON Pointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Pointer returns a JSON Pointer (RFC-6901), in its URI fragment identifier
+// representation, which refers to the member identified by the provided
+// reference tokens. For example, Pointer("profile", "color") returns
+// "#/profile/color".
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+	return "#" + (&url.URL{Fragment: b.String()}).EscapedFragment()
+}
+
+// A ValidationProblem is a Problem which reports every reason that a request
+// is invalid in a single response, as shown in section 3 of RFC-9457.
+type ValidationProblem struct {
+	Problem
+
+	// Errors contains each of the validation errors found in the request.
+	Errors []ValidationError `json:"errors,omitempty" xml:"errors>i,omitempty"`
+}
+
+// NewValidationProblem returns a new ValidationProblem with the status set to
+// 422 Unprocessable Entity, and no errors.
+func NewValidationProblem() *ValidationProblem {
+	return &ValidationProblem{
+		Problem: *NewStatusProblem(http.StatusUnprocessableEntity),
+	}
+}
+
+// FromValidationErrors returns a new ValidationProblem containing an error for
+// each of the provided errors. Errors which are, or wrap, a ValidationError are
+// added as-is, while the messages of all other errors are used as the detail of
+// an error with no location.
+func FromValidationErrors(errs ...error) *ValidationProblem {
+	p := NewValidationProblem()
+	for _, err := range errs {
+		var ve ValidationError
+		if errors.As(err, &ve) {
+			p.Add(ve)
+		} else {
+			p.Add(ValidationError{Detail: err.Error()})
+		}
+	}
+	return p
+}
+
+// Add appends the provided validation error to the problem.
+func (p *ValidationProblem) Add(err ValidationError) *ValidationProblem {
+	p.Errors = append(p.Errors, err)
+	return p
+}
+
+// AddPointer appends a validation error for the member of the request body
+// identified by the provided JSON Pointer.
+func (p *ValidationProblem) AddPointer(pointer, detail string) *ValidationProblem {
+	return p.Add(ValidationError{Detail: detail, Pointer: pointer})
+}
+
+// AddParameter appends a validation error for the named query parameter.
+func (p *ValidationProblem) AddParameter(name, detail string) *ValidationProblem {
+	return p.Add(ValidationError{Detail: detail, Parameter: name})
+}
+
+// AddHeader appends a validation error for the named request header.
+func (p *ValidationProblem) AddHeader(name, detail string) *ValidationProblem {
+	return p.Add(ValidationError{Detail: detail, Header: name})
+}
+
+// WithType sets the type field to the provided string.
+func (p *ValidationProblem) WithType(typ string) *ValidationProblem {
+	p.Type = typ
+	return p
+}
+
+// WithTitle sets the title field to the provided string.
+func (p *ValidationProblem) WithTitle(title string) *ValidationProblem {
+	p.Title = title
+	return p
+}
+
+// WithStatus sets the status field to the provided int.
+//
+// If no title is set then this call will also set the title to the return
+// value of http.StatusText for the provided status code.
+func (p *ValidationProblem) WithStatus(status int) *ValidationProblem {
+	p.Problem.WithStatus(status)
+	return p
+}
+
+// WithDetail sets the detail message to the provided string.
+func (p *ValidationProblem) WithDetail(detail string) *ValidationProblem {
+	p.Detail = detail
+	return p
+}
+
+// WithDetailf behaves identically to WithDetail, but allows consumers to
+// provide a format string and arguments which will be formatted internally.
+func (p *ValidationProblem) WithDetailf(format string, args ...interface{}) *ValidationProblem {
+	p.Detail = fmt.Sprintf(format, args...)
+	return p
+}
+
+// WithError sets the detail message to the provided error, and retains the
+// error as the cause of the problem.
+func (p *ValidationProblem) WithError(err error) *ValidationProblem {
+	p.Problem.WithError(err)
+	return p
+}
+
+// WithCause sets the underlying error which caused the problem, without
+// changing its detail message. The cause is returned by Unwrap, but is never
+// serialized.
+func (p *ValidationProblem) WithCause(err error) *ValidationProblem {
+	p.Problem.WithCause(err)
+	return p
+}
+
+// WithInstance sets the instance uri to the provided string.
+func (p *ValidationProblem) WithInstance(instance string) *ValidationProblem {
+	p.Instance = instance
+	return p
+}
+
+// WithHeader sets the named header field to the provided values, replacing
+// any existing values. The header fields of a problem are written along with
+// it by each of the HTTP handlers, but are never serialized.
+func (p *ValidationProblem) WithHeader(name string, values ...string) *ValidationProblem {
+	p.Problem.WithHeader(name, values...)
+	return p
+}
+
+// WithRetryAfter sets the Retry-After header field to the provided delay, in
+// whole seconds rounded up. If the delay is not positive then the header field
+// is removed.
+func (p *ValidationProblem) WithRetryAfter(d time.Duration) *ValidationProblem {
+	p.Problem.WithRetryAfter(d)
+	return p
+}
+
+// Clone returns a copy of the ValidationProblem which can be modified without
+// affecting the original.
+func (p *ValidationProblem) Clone() *ValidationProblem {
+	return &ValidationProblem{
+		Problem: *p.Problem.Clone(),
+		Errors:  append([]ValidationError(nil), p.Errors...),
+	}
+}
+
+// Error implements the error interface and allows a ValidationProblem to be
+// used as a native error.
+func (p *ValidationProblem) Error() string {
+	msgs := make([]string, 0, len(p.Errors))
+	for _, err := range p.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	msg := p.summary()
+	if len(msgs) == 0 {
+		return msg
+	}
+	if msg == "" {
+		return "[" + strings.Join(msgs, "; ") + "]"
+	}
+	return fmt.Sprintf("%s - [%s]", msg, strings.Join(msgs, "; "))
+}
+
+// validationProblem has the same fields as ValidationProblem, but none of its
+// methods, so that it can be encoded without recursing into MarshalXML.
+type validationProblem ValidationProblem
+
+// MarshalXML implements the xml.Marshaler interface and serializes the problem
+// using the XML format described in Appendix B of RFC-9457, with each of the
+// errors written as an "i" element.
+func (p *ValidationProblem) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(struct {
+		XMLName xml.Name `xml:"urn:ietf:rfc:7807 problem"`
+		*validationProblem
+	}{
+		validationProblem: (*validationProblem)(p),
+	})
+}
@@ -0,0 +1,127 @@
+package problems
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		expect string
+	}{
+		{tokens: nil, expect: "#"},
+		{tokens: []string{"age"}, expect: "#/age"},
+		{tokens: []string{"profile", "color"}, expect: "#/profile/color"},
+		{tokens: []string{"items", "0", "a/b", "m~n"}, expect: "#/items/0/a~1b/m~0n"},
+		{tokens: []string{"c%d", "e^f"}, expect: "#/c%25d/e%5Ef"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expect, func(t *testing.T) {
+			if got := Pointer(test.tokens...); got != test.expect {
+				t.Errorf("expected pointer %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestValidationProblem_MarshalJSON(t *testing.T) {
+	problem := NewValidationProblem().
+		WithType("https://example.net/validation-error").
+		WithTitle("Your request is not valid.").
+		AddPointer(Pointer("age"), "must be a positive integer").
+		AddPointer(Pointer("profile", "color"), "must be 'green', 'red' or 'blue'").
+		AddParameter("page", "must be a number").
+		Add(ValidationError{Detail: "is required", Header: "If-Match", Code: "precondition"})
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatalf("failed to marshal validation problem as json: %s", err)
+	}
+
+	expect := `{"type":"https://example.net/validation-error","title":"Your request is not valid.","status":422,"errors":[` +
+		`{"detail":"must be a positive integer","pointer":"#/age"},` +
+		`{"detail":"must be 'green', 'red' or 'blue'","pointer":"#/profile/color"},` +
+		`{"detail":"must be a number","parameter":"page"},` +
+		`{"detail":"is required","header":"If-Match","code":"precondition"}]}`
+	if string(data) != expect {
+		t.Errorf("validation problem does not match expectation:\ngot\n%s\nwant\n%s", string(data), expect)
+	}
+
+	var decoded ValidationProblem
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal validation problem: %s", err)
+	}
+
+	if !reflect.DeepEqual(&decoded, problem) {
+		t.Errorf("validation problem did not round trip:\ngot\n%#+v\nwant\n%#+v", decoded, problem)
+	}
+}
+
+func TestValidationProblem_MarshalXML(t *testing.T) {
+	problem := NewValidationProblem().
+		AddPointer(Pointer("age"), "must be a positive integer").
+		AddParameter("page", "must be a number")
+
+	data, err := xml.Marshal(problem)
+	if err != nil {
+		t.Fatalf("failed to marshal validation problem as xml: %s", err)
+	}
+
+	expect := `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Unprocessable Entity</title><status>422</status>` +
+		`<errors><i><detail>must be a positive integer</detail><pointer>#/age</pointer></i>` +
+		`<i><detail>must be a number</detail><parameter>page</parameter></i></errors></problem>`
+	if string(data) != expect {
+		t.Errorf("validation problem does not match expectation:\ngot\n%s\nwant\n%s", string(data), expect)
+	}
+
+	var decoded ValidationProblem
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal validation problem: %s", err)
+	}
+
+	if !reflect.DeepEqual(&decoded, problem) {
+		t.Errorf("validation problem did not round trip:\ngot\n%#+v\nwant\n%#+v", decoded, problem)
+	}
+}
+
+func TestFromValidationErrors(t *testing.T) {
+	problem := FromValidationErrors(
+		ValidationError{Detail: "must be a positive integer", Pointer: "#/age"},
+		fmt.Errorf("checking profile: %w", ValidationError{Detail: "is required", Pointer: "#/profile"}),
+		errors.New("request body is empty"),
+	)
+
+	if problem.Status != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, problem.Status)
+	}
+
+	expect := []ValidationError{
+		{Detail: "must be a positive integer", Pointer: "#/age"},
+		{Detail: "is required", Pointer: "#/profile"},
+		{Detail: "request body is empty"},
+	}
+	if !reflect.DeepEqual(problem.Errors, expect) {
+		t.Errorf("unexpected validation errors: %#+v", problem.Errors)
+	}
+
+	if msg := problem.Error(); msg != "Unprocessable Entity (422) - [#/age: must be a positive integer; #/profile: is required; request body is empty]" {
+		t.Errorf("unexpected error message: %q", msg)
+	}
+}
+
+func TestValidationProblem_Clone(t *testing.T) {
+	original := NewValidationProblem().AddPointer("#/age", "must be a positive integer")
+	clone := original.Clone().AddPointer("#/name", "is required")
+	clone.Errors[0].Detail = "modified"
+
+	if len(original.Errors) != 1 || original.Errors[0].Detail != "must be a positive integer" {
+		t.Errorf("modifying a clone modified the original problem: %#+v", original.Errors)
+	}
+}
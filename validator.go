@@ -0,0 +1,109 @@
+package problems
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StrictValidator is a Validator which enables every check defined by
+// Validator.
+var StrictValidator = Validator{
+	RequireAbsoluteType:  true,
+	RequireStatusRange:   true,
+	RequireValidInstance: true,
+	RequireStandardTitle: true,
+}
+
+// A Validator validates problems against a configurable set of the
+// requirements of RFC-9457, in addition to the checks always performed by
+// Problem.Validate.
+//
+// The zero value of Validator performs only the checks of Problem.Validate.
+// See StrictValidator for a Validator which enables every check.
+type Validator struct {
+	// RequireAbsoluteType requires the problem's type to be an absolute URI,
+	// such as "about:blank" or "https://example.com/probs/out-of-credit".
+	RequireAbsoluteType bool
+
+	// RequireStatusRange requires the problem's status, if set, to be a valid
+	// HTTP status code between 100 and 599.
+	RequireStatusRange bool
+
+	// RequireValidInstance requires the problem's instance, if set, to be a
+	// valid URI reference.
+	RequireValidInstance bool
+
+	// RequireStandardTitle requires problems whose type is "about:blank" to
+	// use the standard reason phrase of their status code as their title.
+	// Problems whose status code has no standard reason phrase may use any
+	// title.
+	RequireStandardTitle bool
+}
+
+// ValidateWith behaves identically to Validate, but validates the Problem
+// using the provided Validator.
+//
+// Every violation found is reported in the returned error, which is an
+// *ErrInvalidProblem. The individual violations can be inspected using
+// errors.Is and errors.As.
+func (p *Problem) ValidateWith(v Validator) (*ValidProblem, error) {
+	typ, err := v.validate(p)
+	if err != nil {
+		return nil, err
+	}
+	return newValidProblem(p, typ), nil
+}
+
+// ValidateWith behaves identically to Validate, but validates the
+// ExtendedProblem using the provided Validator. See Problem.ValidateWith for
+// more information.
+func (p *ExtendedProblem[T]) ValidateWith(v Validator) (*ValidExtendedProblem[T], error) {
+	typ, err := v.validate(&p.Problem)
+	if err != nil {
+		return nil, err
+	}
+	return newValidExtendedProblem(p, typ), nil
+}
+
+// validate checks p against every requirement enabled on the Validator, and
+// returns its parsed type URI if it is valid.
+func (v Validator) validate(p *Problem) (*url.URL, error) {
+	var violations []error
+
+	if len(p.Title) == 0 {
+		violations = append(violations, ErrTitleMustBeSet)
+	}
+
+	typ, err := url.Parse(p.Type)
+	switch {
+	case err != nil:
+		violations = append(violations, NewErrInvalidProblemType(p.Type, err))
+	case v.RequireAbsoluteType && p.Type != "" && !typ.IsAbs():
+		violations = append(violations, NewErrInvalidProblemType(p.Type, errors.New("uri must be absolute")))
+	}
+
+	if v.RequireStatusRange && p.Status != 0 && (p.Status < 100 || p.Status > 599) {
+		violations = append(violations, NewErrInvalidStatus(p.Status))
+	}
+
+	if v.RequireValidInstance && p.Instance != "" {
+		if _, err := url.Parse(p.Instance); err != nil {
+			violations = append(violations, NewErrInvalidInstance(p.Instance, err))
+		} else if strings.ContainsAny(p.Instance, " \t\r\n\"<>\\^`{|}") {
+			violations = append(violations, NewErrInvalidInstance(p.Instance, errors.New("uri contains invalid characters")))
+		}
+	}
+
+	if v.RequireStandardTitle && (p.Type == "" || p.Type == DefaultURL) && p.Status != 0 {
+		if expect := http.StatusText(p.Status); expect != "" && p.Title != "" && p.Title != expect {
+			violations = append(violations, NewErrNonStandardTitle(p.Title, expect))
+		}
+	}
+
+	if len(violations) > 0 {
+		return nil, &ErrInvalidProblem{Violations: violations}
+	}
+	return typ, nil
+}
@@ -0,0 +1,129 @@
+package problems
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestProblem_ValidateWith(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		problem   Problem
+		expect    []any
+	}{
+		{
+			name:      "should accept valid problems",
+			validator: StrictValidator,
+			problem: Problem{
+				Type:     "https://example.com/probs/out-of-credit",
+				Title:    "You do not have enough credit.",
+				Status:   http.StatusForbidden,
+				Instance: "/account/12345/msgs/abc",
+			},
+		},
+		{
+			name:      "should accept standard about:blank problems",
+			validator: StrictValidator,
+			problem:   *NewStatusProblem(http.StatusNotFound),
+		},
+		{
+			name:      "should accept relative types when not strict",
+			validator: Validator{},
+			problem:   Problem{Type: "relative/garbage", Title: "Oops", Status: 1000},
+		},
+		{
+			name:      "should reject relative types",
+			validator: Validator{RequireAbsoluteType: true},
+			problem:   Problem{Type: "relative/garbage", Title: "Oops"},
+			expect:    []any{new(*ErrInvalidProblemType)},
+		},
+		{
+			name:      "should reject statuses outside of the valid range",
+			validator: Validator{RequireStatusRange: true},
+			problem:   Problem{Type: DefaultURL, Title: "Oops", Status: 1000},
+			expect:    []any{new(*ErrInvalidStatus)},
+		},
+		{
+			name:      "should reject invalid instances",
+			validator: Validator{RequireValidInstance: true},
+			problem:   Problem{Type: DefaultURL, Title: "Oops", Instance: "/errors/not valid"},
+			expect:    []any{new(*ErrInvalidInstance)},
+		},
+		{
+			name:      "should reject non-standard about:blank titles",
+			validator: Validator{RequireStandardTitle: true},
+			problem:   Problem{Type: DefaultURL, Title: "Missing", Status: http.StatusNotFound},
+			expect:    []any{new(*ErrNonStandardTitle)},
+		},
+		{
+			name:      "should accept any about:blank title for unregistered statuses",
+			validator: StrictValidator,
+			problem:   Problem{Type: DefaultURL, Title: "Network Timeout", Status: 599},
+		},
+		{
+			name:      "should report every violation",
+			validator: StrictValidator,
+			problem:   Problem{Type: "::/", Status: 42, Instance: "%zz"},
+			expect: []any{
+				ErrTitleMustBeSet,
+				new(*ErrInvalidProblemType),
+				new(*ErrInvalidStatus),
+				new(*ErrInvalidInstance),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			valid, err := test.problem.ValidateWith(test.validator)
+			if len(test.expect) == 0 {
+				if err != nil || valid == nil {
+					t.Errorf("expected problem to be valid, got %v", err)
+				}
+				return
+			}
+
+			var invalid *ErrInvalidProblem
+			if !errors.As(err, &invalid) {
+				t.Fatalf("expected an ErrInvalidProblem, got %v", err)
+			}
+
+			if len(invalid.Violations) != len(test.expect) {
+				t.Errorf("expected %d violations, got %d: %v", len(test.expect), len(invalid.Violations), err)
+			}
+
+			for _, expect := range test.expect {
+				if target, ok := expect.(error); ok {
+					if !errors.Is(err, target) {
+						t.Errorf("expected error to match %v, got %v", target, err)
+					}
+				} else if !errors.As(err, expect) {
+					t.Errorf("expected error to contain %T, got %v", expect, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExtendedProblem_ValidateWith(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30})
+
+	valid, err := problem.ValidateWith(StrictValidator)
+	if err != nil {
+		t.Fatalf("extended problem is not valid but should be: %s", err)
+	}
+
+	if valid.IntoExtendedProblem().Extensions.Balance != 30 {
+		t.Errorf("extensions were not retained by the valid problem")
+	}
+
+	if _, err := problem.WithType("probs/out-of-credit").ValidateWith(StrictValidator); err == nil {
+		t.Error("expected relative problem type to be rejected")
+	}
+}
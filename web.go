@@ -1,36 +1,289 @@
 package problems
 
 import (
+	"bytes"
 	"encoding/json"
-	"encoding/xml"
+	"fmt"
+	"html/template"
+	"log/slog"
 	"net/http"
+	"strconv"
 )
 
+// htmlTemplate is used to render problems for clients which prefer HTML.
+var htmlTemplate = template.Must(template.New("problem").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Status}}
+<p>Status: {{.Status}}</p>
+{{- end}}
+{{- if .Detail}}
+<p>{{.Detail}}</p>
+{{- end}}
+{{- if and .Type (ne .Type "about:blank")}}
+<p>Type: <a href="{{.Type}}">{{.Type}}</a></p>
+{{- end}}
+{{- if .Instance}}
+<p>Instance: {{.Instance}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
 // ProblemHandler returns a http.HandlerFunc which writes a provided problem
-// to a http.ResponseWriter as JSON with the status code.
-func ProblemHandler(p *Problem) http.HandlerFunc {
+// to a http.ResponseWriter as JSON with the status code. The problem is
+// redacted according to DefaultRedactionPolicy before it is written, and any
+// error is reported to the OnError function of DefaultWriter.
+func ProblemHandler(p Details) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", ProblemMediaType)
-		if p.Status != 0 {
-			w.WriteHeader(p.Status)
+		if err := WriteJSONProblem(w, p); err != nil {
+			DefaultWriter.reportError(r, err)
 		}
-		_ = json.NewEncoder(w).Encode(p)
 	}
 }
 
 // XMLProblemHandler returns a http.HandlerFunc which writes a provided problem
-// to a http.ResponseWriter as XML with the status code.
-func XMLProblemHandler(p *Problem) http.HandlerFunc {
+// to a http.ResponseWriter as XML with the status code. The problem is
+// redacted according to DefaultRedactionPolicy before it is written, and any
+// error is reported to the OnError function of DefaultWriter.
+func XMLProblemHandler(p Details) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", ProblemMediaTypeXML)
-		if p.Status != 0 {
-			w.WriteHeader(p.Status)
+		if err := WriteXMLProblem(w, p); err != nil {
+			DefaultWriter.reportError(r, err)
+		}
+	}
+}
+
+// WriteProblem writes the provided problem to w using DefaultWriter, and
+// returns any error encountered. See Writer.Write for more information.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p Details) error {
+	return DefaultWriter.Write(w, r, p)
+}
+
+// WriteJSONProblem writes the provided problem to w as JSON with the status
+// code, after redacting it according to DefaultRedactionPolicy. The problem is
+// encoded before anything is written, so that if it cannot be encoded a 500
+// Internal Server Error problem is written in its place, and the encoding error
+// is returned.
+func WriteJSONProblem(w http.ResponseWriter, p Details) error {
+	return writeProblem(w, ProblemMediaType, DefaultRedactionPolicy.Redact(p))
+}
+
+// WriteXMLProblem behaves identically to WriteJSONProblem, but writes the
+// problem as XML.
+func WriteXMLProblem(w http.ResponseWriter, p Details) error {
+	return writeProblem(w, ProblemMediaTypeXML, DefaultRedactionPolicy.Redact(p))
+}
+
+// ValidProblemHandler returns a http.HandlerFunc which writes a provided,
+// validated, problem to a http.ResponseWriter using DefaultWriter, which
+// chooses the media type which best matches the request's Accept header.
+func ValidProblemHandler(p Validated) http.HandlerFunc {
+	return NegotiatedProblemHandler(p)
+}
+
+// NegotiatedProblemHandler returns a http.HandlerFunc which writes a provided
+// problem to a http.ResponseWriter using DefaultWriter, which chooses the media
+// type which best matches the request's Accept header. Any error is reported to
+// the OnError function of DefaultWriter. See Writer for more information.
+func NegotiatedProblemHandler(p Details) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = DefaultWriter.Write(w, r, p)
+	}
+}
+
+// A Writer writes problems to a http.ResponseWriter using the media type which
+// best matches the Accept header of the request being served, taking the
+// quality values of each media range into account.
+//
+// The zero value of Writer is ready to use and offers every supported media
+// type, preferring ProblemMediaType.
+type Writer struct {
+	// Offers lists the media types which the Writer may produce, in order of
+	// preference. The supported media types are ProblemMediaType,
+	// ProblemMediaTypeXML, JSONMediaType, HTMLMediaType, and
+	// ConciseProblemMediaType. If empty, all of the supported media types are
+	// offered in that order.
+	Offers []string
+
+	// Strict causes the Writer to respond with a 406 Not Acceptable problem
+	// when none of the Offers are acceptable to the client. Otherwise, the
+	// Writer falls back to the first of its Offers.
+	Strict bool
+
+	// ErrorProblem converts errors passed to WriteError which do not contain
+	// a problem into the problem that is written in their place. If nil, a
+	// 500 Internal Server Error problem is written.
+	ErrorProblem func(err error) Details
+
+	// Redaction controls which problem details are redacted before they are
+	// written. If nil, DefaultRedactionPolicy is used.
+	Redaction *RedactionPolicy
+
+	// Correlation, if set, adds the correlation ID of the request being
+	// served to each problem, and to the response headers.
+	Correlation *Correlation
+
+	// Logger, if set, logs every problem written by the Writer, before it is
+	// redacted. Each problem is logged at the level returned by LogLevel.
+	Logger *slog.Logger
+
+	// LogLevel chooses the level at which a problem with the provided status
+	// is logged. If nil, StatusLevel is used.
+	LogLevel func(status int) slog.Level
+
+	// OnError, if set, is called with every error returned by Write, such as
+	// when a problem cannot be encoded. It allows errors to be reported when
+	// problems are written by a handler, which has no caller to return them
+	// to.
+	OnError func(r *http.Request, err error)
+}
+
+// Handler returns a http.HandlerFunc which writes the provided problem using
+// the Writer.
+func (pw *Writer) Handler(p Details) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_ = pw.Write(w, r, p)
+	}
+}
+
+// WriteValid behaves identically to Write, but only accepts problems which
+// have been validated.
+func (pw *Writer) WriteValid(w http.ResponseWriter, r *http.Request, p Validated) error {
+	return pw.Write(w, r, p)
+}
+
+// Write writes the provided problem to w, using the media type which best
+// matches the Accept header of r. The problem is logged, redacted according to
+// the Writer's RedactionPolicy, and annotated with the request's correlation
+// ID, before it is written.
+//
+// The problem is encoded before anything is written, so that the response can
+// include a Content-Length. If the problem cannot be encoded, a 500 Internal
+// Server Error problem is written in its place. Any error encountered is both
+// returned and passed to OnError.
+func (pw *Writer) Write(w http.ResponseWriter, r *http.Request, p Details) error {
+	err := pw.write(w, r, p)
+	if err != nil {
+		pw.reportError(r, err)
+	}
+	return err
+}
+
+// write implements Write.
+func (pw *Writer) write(w http.ResponseWriter, r *http.Request, p Details) error {
+	var id string
+	if pw.Correlation != nil {
+		id = pw.Correlation.ID(r)
+	}
+	pw.log(r, p, id)
+
+	p = pw.redactionPolicy().Redact(p)
+	if pw.Correlation != nil {
+		p = pw.Correlation.apply(w, id, p)
+	}
+
+	offers := pw.Offers
+	if len(offers) == 0 {
+		offers = defaultOffers
+	}
+
+	w.Header().Add("Vary", "Accept")
+
+	mediaType, ok := negotiate(r.Header.Values("Accept"), offers)
+	if !ok {
+		if pw.Strict {
+			return writeProblem(w, ProblemMediaType, NewStatusProblem(http.StatusNotAcceptable))
 		}
-		_ = xml.NewEncoder(w).Encode(struct {
-			XMLName xml.Name `xml:"urn:ietf:rfc:7807 problem"`
-			Problem
-		}{
-			Problem: *p,
-		})
+		mediaType = offers[0]
+	}
+
+	return writeProblem(w, mediaType, p)
+}
+
+// reportError passes err to the Writer's OnError function, if it has one.
+func (pw *Writer) reportError(r *http.Request, err error) {
+	if pw.OnError != nil {
+		pw.OnError(r, err)
+	}
+}
+
+// writeProblem writes p to w using the provided media type, along with its
+// header fields and status code.
+//
+// The problem is encoded before anything is written. If it cannot be encoded,
+// a 500 Internal Server Error problem with the same instance is written in its
+// place, and the encoding error is returned.
+func writeProblem(w http.ResponseWriter, mediaType string, p Details) error {
+	body, err := encodeProblem(mediaType, p)
+	if err != nil {
+		err = fmt.Errorf("%s: encoding problem: %w", errPrefix, err)
+
+		// A Problem without extensions can always be encoded.
+		p = NewStatusProblem(http.StatusInternalServerError).WithInstance(p.details().Instance)
+		body, _ = encodeProblem(mediaType, p)
+	}
+
+	problem := p.details()
+	copyHeader(w.Header(), problem.header)
+	if mediaType == HTMLMediaType {
+		w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", mediaType)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if problem.Status != 0 {
+		w.WriteHeader(problem.Status)
+	}
+
+	if _, werr := w.Write(body); werr != nil && err == nil {
+		err = fmt.Errorf("%s: writing problem: %w", errPrefix, werr)
+	}
+	return err
+}
+
+// encodeProblem encodes p using the provided media type.
+func encodeProblem(mediaType string, p Details) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+	switch mediaType {
+	case ProblemMediaTypeXML:
+		err = encodeXML(&buf, p)
+	case HTMLMediaType:
+		err = htmlTemplate.Execute(&buf, p.details())
+	case ConciseProblemMediaType:
+		return MarshalCBOR(p)
+	default:
+		err = json.NewEncoder(&buf).Encode(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// redactionPolicy returns the RedactionPolicy used by the Writer.
+func (pw *Writer) redactionPolicy() *RedactionPolicy {
+	if pw.Redaction != nil {
+		return pw.Redaction
+	}
+	return DefaultRedactionPolicy
+}
+
+// copyHeader sets each of the header fields of a problem on the response
+// header dst, replacing any values which have already been set.
+func copyHeader(dst http.Header, header *http.Header) {
+	if header == nil {
+		return
+	}
+	for name, values := range *header {
+		dst[name] = append([]string(nil), values...)
 	}
 }
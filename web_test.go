@@ -3,10 +3,14 @@ package problems
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func testServer(funcs ...http.HandlerFunc) *httptest.Server {
@@ -61,6 +65,33 @@ func TestJSONProblems(t *testing.T) {
 	}
 }
 
+func TestJSONProblems_extended(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30})
+
+	server := testServer(ProblemHandler(problem))
+	defer server.Close()
+
+	w, err := getResponse("/", server)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if w.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected HTTP status code to be %d, got %d", http.StatusForbidden, w.StatusCode)
+	}
+
+	var response ExtendedProblem[creditProblemExt]
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Error(err)
+	}
+
+	if response.Extensions.Balance != 30 {
+		t.Errorf("Expected response Balance to be %v, but got %v", 30, response.Extensions.Balance)
+	}
+}
+
 func TestXMLProblems(t *testing.T) {
 	notFound := NewDetailedProblem(http.StatusNotFound, "That thing doesn't exist.")
 
@@ -94,3 +125,345 @@ func TestXMLProblems(t *testing.T) {
 		t.Errorf("Expected response Detail to be %q, but got %q", notFound.Detail, response.Detail)
 	}
 }
+
+func TestWriter(t *testing.T) {
+	notFound := NewDetailedProblem(http.StatusNotFound, "That thing doesn't exist.")
+	valid, err := notFound.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+
+	tests := []struct {
+		name          string
+		writer        Writer
+		problem       Details
+		accept        string
+		expectStatus  int
+		expectType    string
+		expectContent string
+	}{
+		{
+			name:          "should default to problem json",
+			problem:       notFound,
+			expectStatus:  http.StatusNotFound,
+			expectType:    ProblemMediaType,
+			expectContent: `"detail":"That thing doesn't exist."`,
+		},
+		{
+			name:          "should write xml when preferred",
+			problem:       notFound,
+			accept:        "application/problem+json;q=0.5, application/problem+xml",
+			expectStatus:  http.StatusNotFound,
+			expectType:    ProblemMediaTypeXML,
+			expectContent: `<detail>That thing doesn&#39;t exist.</detail>`,
+		},
+		{
+			name: "should write extended problems as xml",
+			problem: NewExt[creditProblemExt]().
+				WithStatus(http.StatusForbidden).
+				WithExtension(creditProblemExt{Balance: 30}),
+			accept:        "application/problem+xml",
+			expectStatus:  http.StatusForbidden,
+			expectType:    ProblemMediaTypeXML,
+			expectContent: `<balance>30</balance>`,
+		},
+		{
+			name:          "should write plain json",
+			problem:       valid,
+			accept:        "application/json",
+			expectStatus:  http.StatusNotFound,
+			expectType:    JSONMediaType,
+			expectContent: `"title":"Not Found"`,
+		},
+		{
+			name:          "should write html",
+			problem:       NewExt[creditProblemExt]().WithStatus(http.StatusForbidden),
+			accept:        "text/html,application/xhtml+xml",
+			expectStatus:  http.StatusForbidden,
+			expectType:    HTMLMediaType + "; charset=utf-8",
+			expectContent: `<h1>Forbidden</h1>`,
+		},
+		{
+			name:          "should fall back to first offer",
+			writer:        Writer{Offers: []string{ProblemMediaTypeXML}},
+			problem:       notFound,
+			accept:        "image/png",
+			expectStatus:  http.StatusNotFound,
+			expectType:    ProblemMediaTypeXML,
+			expectContent: `<title>Not Found</title>`,
+		},
+		{
+			name:          "should respond not acceptable in strict mode",
+			writer:        Writer{Strict: true},
+			problem:       notFound,
+			accept:        "image/png",
+			expectStatus:  http.StatusNotAcceptable,
+			expectType:    ProblemMediaType,
+			expectContent: `"title":"Not Acceptable"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			test.writer.Write(rec, req, test.problem)
+
+			if rec.Code != test.expectStatus {
+				t.Errorf("Expected HTTP status code to be %d, got %d", test.expectStatus, rec.Code)
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != test.expectType {
+				t.Errorf("Expected Content-Type to be %q, got %q", test.expectType, ct)
+			}
+
+			if vary := rec.Header().Get("Vary"); vary != "Accept" {
+				t.Errorf("Expected Vary to be %q, got %q", "Accept", vary)
+			}
+
+			if !strings.Contains(rec.Body.String(), test.expectContent) {
+				t.Errorf("Expected body to contain %q, got %q", test.expectContent, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestValidProblemHandler(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithStatus(http.StatusForbidden).
+		WithExtension(creditProblemExt{Balance: 30})
+
+	valid, err := problem.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+
+	server := testServer(ValidProblemHandler(valid))
+	defer server.Close()
+
+	w, err := getResponse("/", server)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if w.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected HTTP status code to be %d, got %d", http.StatusForbidden, w.StatusCode)
+	}
+
+	var response ExtendedProblem[creditProblemExt]
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Error(err)
+	}
+
+	if response.Extensions.Balance != 30 {
+		t.Errorf("Expected response Balance to be %v, but got %v", 30, response.Extensions.Balance)
+	}
+}
+
+func TestWriter_Header(t *testing.T) {
+	ext := NewExt[map[string]any]().
+		WithStatus(http.StatusServiceUnavailable).
+		WithExtension(map[string]any{"component": "db"}).
+		WithRetryAfter(time.Minute)
+	valid, err := ext.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+	template := NewTemplate(NewStatusProblem(http.StatusUnauthorized))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		field   string
+		expect  string
+	}{
+		{
+			name:    "ProblemHandler",
+			handler: ProblemHandler(template.WithHeader("WWW-Authenticate", `Bearer realm="api"`)),
+			field:   "WWW-Authenticate",
+			expect:  `Bearer realm="api"`,
+		},
+		{name: "XMLProblemHandler", handler: XMLProblemHandler(ext), field: "Retry-After", expect: "60"},
+		{name: "ValidProblemHandler", handler: ValidProblemHandler(valid), field: "Retry-After", expect: "60"},
+		{name: "NegotiatedProblemHandler", handler: NegotiatedProblemHandler(ext), field: "Retry-After", expect: "60"},
+		{
+			name:    "Writer with redaction",
+			handler: (&Writer{Redaction: &RedactionPolicy{RedactServerErrors: true}}).Handler(ext),
+			field:   "Retry-After",
+			expect:  "60",
+		},
+		{
+			name:    "Writer with a validation problem",
+			handler: (&Writer{}).Handler(NewValidationProblem().WithHeader("Accept-Patch", "application/merge-patch+json")),
+			field:   "Accept-Patch",
+			expect:  "application/merge-patch+json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			test.handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// The recorded result only contains the header fields which were
+			// set before WriteHeader was called.
+			if got := w.Result().Header.Get(test.field); got != test.expect {
+				t.Errorf("expected %s header %q, got %q", test.field, test.expect, got)
+			}
+		})
+	}
+}
+
+func TestWriter_StrictHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "image/png")
+	w := httptest.NewRecorder()
+
+	(&Writer{Strict: true}).Write(w, r, NewStatusProblem(http.StatusTooManyRequests).WithRetryAfter(time.Second))
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Errorf("expected status %d, got %d", http.StatusNotAcceptable, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "" {
+		t.Errorf("expected the header fields of the unwritten problem to be left out, got Retry-After %q", got)
+	}
+}
+
+// failingExt is an extension which always fails to serialize.
+type failingExt struct{}
+
+var errFailingExt = errors.New("failing extension")
+
+func (failingExt) MarshalJSON() ([]byte, error) {
+	return nil, errFailingExt
+}
+
+func TestWriteProblem(t *testing.T) {
+	failing := NewExt[failingExt]().
+		WithStatus(http.StatusConflict).
+		WithInstance("/orders/1").
+		WithRetryAfter(time.Second)
+
+	tests := []struct {
+		name         string
+		write        func(w http.ResponseWriter, r *http.Request) error
+		accept       string
+		expectStatus int
+		expectType   string
+		expectErr    error
+	}{
+		{
+			name:         "should write problems",
+			write:        func(w http.ResponseWriter, r *http.Request) error { return WriteProblem(w, r, NotFound()) },
+			expectStatus: http.StatusNotFound,
+			expectType:   ProblemMediaType,
+		},
+		{
+			name:         "should fall back to an internal server error",
+			write:        func(w http.ResponseWriter, r *http.Request) error { return WriteProblem(w, r, failing) },
+			expectStatus: http.StatusInternalServerError,
+			expectType:   ProblemMediaType,
+			expectErr:    errFailingExt,
+		},
+		{
+			name:         "should fall back to an internal server error in the negotiated media type",
+			write:        func(w http.ResponseWriter, r *http.Request) error { return WriteProblem(w, r, failing) },
+			accept:       ConciseProblemMediaType,
+			expectStatus: http.StatusInternalServerError,
+			expectType:   ConciseProblemMediaType,
+			expectErr:    errFailingExt,
+		},
+		{
+			name:         "should write JSON problems",
+			write:        func(w http.ResponseWriter, r *http.Request) error { return WriteJSONProblem(w, failing) },
+			accept:       ProblemMediaTypeXML,
+			expectStatus: http.StatusInternalServerError,
+			expectType:   ProblemMediaType,
+			expectErr:    errFailingExt,
+		},
+		{
+			name:         "should write XML problems",
+			write:        func(w http.ResponseWriter, r *http.Request) error { return WriteXMLProblem(w, failing) },
+			expectStatus: http.StatusInternalServerError,
+			expectType:   ProblemMediaTypeXML,
+			expectErr:    errFailingExt,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.accept != "" {
+				r.Header.Set("Accept", test.accept)
+			}
+			w := httptest.NewRecorder()
+
+			if err := test.write(w, r); !errors.Is(err, test.expectErr) {
+				t.Errorf("expected error %v, got %v", test.expectErr, err)
+			}
+
+			resp := w.Result()
+			if resp.StatusCode != test.expectStatus {
+				t.Errorf("expected status %d, got %d", test.expectStatus, resp.StatusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != test.expectType {
+				t.Errorf("expected content type %q, got %q", test.expectType, ct)
+			}
+			if cl := resp.Header.Get("Content-Length"); cl != strconv.Itoa(w.Body.Len()) {
+				t.Errorf("expected content length %d, got %q", w.Body.Len(), cl)
+			}
+
+			if test.expectErr == nil {
+				return
+			}
+			if ra := resp.Header.Get("Retry-After"); ra != "" {
+				t.Errorf("expected the header of the failed problem to be discarded, got %q", ra)
+			}
+			if !strings.Contains(w.Body.String(), "/orders/1") {
+				t.Errorf("expected the fallback problem to keep its instance, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWriter_OnError(t *testing.T) {
+	var reported []error
+	onError := func(r *http.Request, err error) {
+		reported = append(reported, err)
+	}
+
+	original := DefaultWriter
+	DefaultWriter = &Writer{OnError: onError}
+	defer func() { DefaultWriter = original }()
+
+	failing := NewExt[failingExt]().WithStatus(http.StatusConflict)
+	valid, err := failing.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+	handlers := []http.HandlerFunc{
+		(&Writer{OnError: onError}).Handler(failing),
+		ProblemHandler(failing),
+		XMLProblemHandler(failing),
+		ValidProblemHandler(valid),
+		NegotiatedProblemHandler(failing),
+		HandlerFunc(func(w http.ResponseWriter, r *http.Request) error { return failing }).ServeHTTP,
+		ProblemHandler(NotFound()),
+	}
+	for _, handler := range handlers {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	if len(reported) != 6 {
+		t.Fatalf("expected 6 errors to be reported, got %d: %v", len(reported), reported)
+	}
+	for _, err := range reported {
+		if !errors.Is(err, errFailingExt) {
+			t.Errorf("expected the encoding error to be reported, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package problems
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ProblemNamespace is the XML namespace of the problem details root element,
+// as defined by Appendix B of RFC-9457.
+const ProblemNamespace = "urn:ietf:rfc:7807"
+
+var (
+	// xmlRoot is the root element of every problem details XML document.
+	xmlRoot = xml.StartElement{Name: xml.Name{Space: ProblemNamespace, Local: "problem"}}
+
+	// xmlItem is the element used to represent each entry in an array.
+	xmlItem = xml.StartElement{Name: xml.Name{Local: "i"}}
+)
+
+// xmlProblem wraps a Problem in the root element defined by RFC-9457 for the
+// XML representation of a problem details object.
+type xmlProblem struct {
+	XMLName xml.Name `xml:"urn:ietf:rfc:7807 problem"`
+	Problem
+}
+
+// encodeXML writes the provided problem to w as an XML problem details
+// document. Problems which do not implement xml.Marshaler are written using
+// only their standard members.
+//
+// Problem does not implement xml.Marshaler itself so that types which embed it
+// continue to serialize their own fields.
+func encodeXML(w io.Writer, p Details) error {
+	if m, ok := p.(xml.Marshaler); ok {
+		return xml.NewEncoder(w).Encode(m)
+	}
+	return xml.NewEncoder(w).Encode(xmlProblem{Problem: *p.details()})
+}
+
+// MarshalXML implements the xml.Marshaler interface and ensures that a
+// ValidProblem is properly serialized into XML.
+func (p *ValidProblem) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	return e.Encode(xmlProblem{Problem: *p.IntoProblem()})
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. The decoded problem
+// is validated in the same way as by Problem.Validate, and the validation error
+// is returned if it is invalid.
+func (p *ValidProblem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var problem Problem
+	if err := d.DecodeElement(&problem, &start); err != nil {
+		return err
+	}
+
+	valid, err := problem.Validate()
+	if err != nil {
+		return err
+	}
+
+	*p = *valid
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface and ensures that a
+// ValidExtendedProblem is properly serialized into XML.
+func (p *ValidExtendedProblem[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return p.IntoExtendedProblem().MarshalXML(e, start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. The decoded problem
+// is validated in the same way as by ExtendedProblem.Validate, and the
+// validation error is returned if it is invalid.
+func (p *ValidExtendedProblem[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var problem ExtendedProblem[T]
+	if err := problem.UnmarshalXML(d, start); err != nil {
+		return err
+	}
+
+	valid, err := problem.Validate()
+	if err != nil {
+		return err
+	}
+
+	*p = *valid
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface and serializes the problem
+// using the XML format described in Appendix B of RFC-9457.
+//
+// The members of Extensions are first serialized as JSON, so that the same
+// member names are used by both formats, and are then written as child
+// elements of the problem. Nested objects are written as nested elements, and
+// each entry in an array is written as an "i" element.
+//
+// Like MarshalJSON, MarshalXML has a value receiver so that an ExtendedProblem
+// which is marshalled by value is serialized in the same way.
+func (p ExtendedProblem[T]) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	ext, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return err
+	}
+	if _, err := extensionMembers(ext); err != nil {
+		return err
+	}
+
+	if err := e.EncodeToken(xmlRoot); err != nil {
+		return err
+	}
+	if err := encodeStandardMembers(e, &p.Problem); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(ext))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok == nil {
+		// The extensions serialized to null and contain no members.
+		return e.EncodeToken(xmlRoot.End())
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		start, err := memberElement(key.(string))
+		if err != nil {
+			return err
+		}
+		if err := encodeJSONValue(e, dec, start); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(xmlRoot.End())
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface. The standard problem
+// details members are decoded into the embedded Problem, while all remaining
+// child elements are decoded into Extensions.
+//
+// Because XML carries no type information, element content which looks like a
+// JSON number or boolean is decoded as one, unless the corresponding field in
+// Extensions is a string.
+func (p *ExtendedProblem[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var members []*xmlNode
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := decodeStandardMember(d, t, &p.Problem); err == nil {
+				continue
+			} else if !errors.Is(err, errNotStandardMember) {
+				return err
+			}
+
+			node, err := readXMLNode(d, t)
+			if err != nil {
+				return err
+			}
+			members = append(members, node)
+		case xml.EndElement:
+			return decodeXMLNodes(members, &p.Extensions)
+		}
+	}
+}
+
+// encodeStandardMembers writes the standard members of p as child elements,
+// omitting those which are empty in the same way as the JSON representation.
+func encodeStandardMembers(e *xml.Encoder, p *Problem) error {
+	members := []struct {
+		name  string
+		value any
+		omit  bool
+	}{
+		{name: "type", value: p.Type},
+		{name: "title", value: p.Title},
+		{name: "status", value: p.Status, omit: p.Status == 0},
+		{name: "detail", value: p.Detail, omit: p.Detail == ""},
+		{name: "instance", value: p.Instance, omit: p.Instance == ""},
+	}
+
+	for _, m := range members {
+		if m.omit {
+			continue
+		}
+		if err := e.EncodeElement(m.value, xml.StartElement{Name: xml.Name{Local: m.name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errNotStandardMember is returned by decodeStandardMember when the element
+// being decoded is an extension member.
+var errNotStandardMember = errors.New("not a standard member")
+
+// decodeStandardMember decodes the provided element into the matching field of
+// p, or returns errNotStandardMember if the element is not a standard member.
+func decodeStandardMember(d *xml.Decoder, start xml.StartElement, p *Problem) error {
+	switch start.Name.Local {
+	case "type":
+		return d.DecodeElement(&p.Type, &start)
+	case "title":
+		return d.DecodeElement(&p.Title, &start)
+	case "status":
+		return d.DecodeElement(&p.Status, &start)
+	case "detail":
+		return d.DecodeElement(&p.Detail, &start)
+	case "instance":
+		return d.DecodeElement(&p.Instance, &start)
+	default:
+		return errNotStandardMember
+	}
+}
+
+// encodeJSONValue reads the next JSON value from dec and writes it to e as an
+// element named by start.
+func encodeJSONValue(e *xml.Encoder, dec *json.Decoder, start xml.StartElement) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		for dec.More() {
+			child := xmlItem
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if child, err = memberElement(key.(string)); err != nil {
+					return err
+				}
+			}
+			if err := encodeJSONValue(e, dec, child); err != nil {
+				return err
+			}
+		}
+		// Consume the closing delimiter of the object or array.
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		return e.EncodeToken(start.End())
+	case nil:
+		return e.EncodeElement("", start)
+	case json.Number:
+		return e.EncodeElement(v.String(), start)
+	default:
+		return e.EncodeElement(v, start)
+	}
+}
+
+// memberElement returns the element used to represent the member with the
+// provided name, or an ErrInvalidXMLName if the name cannot be used as the name
+// of an XML element.
+func memberElement(name string) (xml.StartElement, error) {
+	if !isXMLName(name) {
+		return xml.StartElement{}, NewErrInvalidXMLName(name)
+	}
+	return xml.StartElement{Name: xml.Name{Local: name}}, nil
+}
+
+// isXMLName reports whether name matches the NCName production of the XML
+// Namespaces specification, which is the Name production of XML 1.0 without
+// any colons. Names containing colons are rejected so that they cannot be
+// mistaken for namespace prefixes.
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !isXMLNameStartChar(r) && (i == 0 || !isXMLNameChar(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isXMLNameStartChar reports whether r matches the NameStartChar production of
+// XML 1.0, excluding the colon.
+func isXMLNameStartChar(r rune) bool {
+	switch {
+	case r == '_', 'A' <= r && r <= 'Z', 'a' <= r && r <= 'z':
+		return true
+	case 0xC0 <= r && r <= 0xD6, 0xD8 <= r && r <= 0xF6, 0xF8 <= r && r <= 0x2FF:
+		return true
+	case 0x370 <= r && r <= 0x37D, 0x37F <= r && r <= 0x1FFF, 0x200C <= r && r <= 0x200D:
+		return true
+	case 0x2070 <= r && r <= 0x218F, 0x2C00 <= r && r <= 0x2FEF, 0x3001 <= r && r <= 0xD7FF:
+		return true
+	case 0xF900 <= r && r <= 0xFDCF, 0xFDF0 <= r && r <= 0xFFFD, 0x10000 <= r && r <= 0xEFFFF:
+		return true
+	}
+	return false
+}
+
+// isXMLNameChar reports whether r matches the NameChar production of XML 1.0,
+// excluding the colon.
+func isXMLNameChar(r rune) bool {
+	switch {
+	case isXMLNameStartChar(r), r == '-', r == '.', '0' <= r && r <= '9', r == 0xB7:
+		return true
+	case 0x300 <= r && r <= 0x36F, 0x203F <= r && r <= 0x2040:
+		return true
+	}
+	return false
+}
+
+// An xmlNode is a generic representation of an XML element which is used to
+// translate extension members into JSON before decoding them.
+type xmlNode struct {
+	name     string
+	text     string
+	children []*xmlNode
+
+	// quoted forces the node's text to be decoded as a JSON string, even if
+	// it looks like a number or boolean.
+	quoted bool
+}
+
+// readXMLNode reads the element opened by start, including all its children.
+func readXMLNode(d *xml.Decoder, start xml.StartElement) (*xmlNode, error) {
+	node := &xmlNode{name: start.Name.Local}
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			child, err := readXMLNode(d, t)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		case xml.EndElement:
+			node.text = text.String()
+			return node, nil
+		}
+	}
+}
+
+// decodeXMLNodes decodes the provided nodes, as the members of a JSON object,
+// into v.
+//
+// Scalar values are first decoded as JSON numbers and booleans where possible.
+// Each time one of them is rejected by the target type, it is instead decoded
+// as a string and decoding is retried.
+func decodeXMLNodes[T any](members []*xmlNode, v *T) error {
+	var buf bytes.Buffer
+	for {
+		buf.Reset()
+		literals := map[int64]*xmlNode{}
+		writeJSONObject(&buf, members, literals)
+
+		var out T
+		err := json.Unmarshal(buf.Bytes(), &out)
+
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			if node, ok := literals[typeErr.Offset]; ok {
+				node.quoted = true
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		*v = out
+		return nil
+	}
+}
+
+// writeJSONObject writes the provided nodes to buf as the members of a JSON
+// object. The end offset of every unquoted literal is recorded in literals.
+func writeJSONObject(buf *bytes.Buffer, members []*xmlNode, literals map[int64]*xmlNode) {
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(m.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		m.writeJSON(buf, literals)
+	}
+	buf.WriteByte('}')
+}
+
+// writeJSON writes the node to buf as a JSON value.
+func (n *xmlNode) writeJSON(buf *bytes.Buffer, literals map[int64]*xmlNode) {
+	if len(n.children) == 0 {
+		text := strings.TrimSpace(n.text)
+		switch {
+		case text == "":
+			buf.WriteString("null")
+		case !n.quoted && isJSONLiteral(text):
+			buf.WriteString(text)
+			literals[int64(buf.Len())] = n
+		default:
+			str, _ := json.Marshal(n.text)
+			buf.Write(str)
+		}
+		return
+	}
+
+	for _, child := range n.children {
+		if child.name != xmlItem.Name.Local {
+			writeJSONObject(buf, n.children, literals)
+			return
+		}
+	}
+
+	buf.WriteByte('[')
+	for i, child := range n.children {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		child.writeJSON(buf, literals)
+	}
+	buf.WriteByte(']')
+}
+
+// isJSONLiteral reports whether text is a JSON number or boolean.
+func isJSONLiteral(text string) bool {
+	if text == "true" || text == "false" {
+		return true
+	}
+	return (text[0] == '-' || (text[0] >= '0' && text[0] <= '9')) && json.Valid([]byte(text))
+}
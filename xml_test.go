@@ -0,0 +1,205 @@
+package problems
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+type accountProblemExt struct {
+	AccountID string            `json:"account_id"`
+	Balance   float64           `json:"balance"`
+	Accounts  []string          `json:"accounts"`
+	Owner     accountOwner      `json:"owner"`
+	Flags     map[string]bool   `json:"flags,omitempty"`
+	Limits    []accountOwner    `json:"limits,omitempty"`
+	Note      *string           `json:"note"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+type accountOwner struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestExtendedProblem_MarshalXML(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithType("https://example.com/probs/out-of-credit").
+		WithTitle("You do not have enough credit.").
+		WithDetail("Your current balance is 30, but that costs 50.").
+		WithInstance("https://example.net/account/12345/msgs/abc").
+		WithExtension(creditProblemExt{
+			Balance:  30,
+			Accounts: []string{"https://example.net/account/12345", "https://example.net/account/67890"},
+		})
+
+	expect := `<problem xmlns="urn:ietf:rfc:7807">` +
+		`<type>https://example.com/probs/out-of-credit</type>` +
+		`<title>You do not have enough credit.</title>` +
+		`<detail>Your current balance is 30, but that costs 50.</detail>` +
+		`<instance>https://example.net/account/12345/msgs/abc</instance>` +
+		`<balance>30</balance>` +
+		`<accounts><i>https://example.net/account/12345</i><i>https://example.net/account/67890</i></accounts>` +
+		`</problem>`
+
+	values := map[string]any{
+		"pointer": problem,
+		"value":   *problem,
+	}
+	for name, value := range values {
+		data, err := xml.Marshal(value)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal extended problem as xml: %s", name, err)
+		}
+
+		if string(data) != expect {
+			t.Errorf("%s: extended problem does not match expectation:\ngot\n%s\nwant\n%s", name, string(data), expect)
+		}
+	}
+}
+
+func TestExtendedProblem_MarshalXML_invalidExtensions(t *testing.T) {
+	problem := NewExt[map[string]int]().WithExtension(map[string]int{"status": 500})
+
+	if _, err := xml.Marshal(problem); err == nil {
+		t.Error("expected an error marshalling reserved extension members")
+	}
+}
+
+func TestExtendedProblem_MarshalXML_invalidNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[string]any
+		member     string
+	}{
+		{name: "should reject names containing spaces", extensions: map[string]any{"a b": 1}, member: "a b"},
+		{name: "should reject names starting with a digit", extensions: map[string]any{"1x": 1}, member: "1x"},
+		{name: "should reject names containing colons", extensions: map[string]any{"a:b": 1}, member: "a:b"},
+		{name: "should reject empty names", extensions: map[string]any{"": 1}, member: ""},
+		{name: "should reject invalid nested names", extensions: map[string]any{"owner": map[string]int{"<x>": 1}}, member: "<x>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := xml.Marshal(NewExt[map[string]any]().WithExtension(test.extensions))
+
+			var nameErr *ErrInvalidXMLName
+			if !errors.As(err, &nameErr) || nameErr.Member != test.member {
+				t.Errorf("expected ErrInvalidXMLName for %q, got %v", test.member, err)
+			}
+		})
+	}
+
+	valid := map[string]any{"_a-b.c": 1, "café": 2, "x1": 3}
+	if _, err := xml.Marshal(NewExt[map[string]any]().WithExtension(valid)); err != nil {
+		t.Errorf("unexpected error marshalling valid names: %s", err)
+	}
+}
+
+func TestExtendedProblem_UnmarshalXML(t *testing.T) {
+	note := "numbers in strings"
+	problem := NewExt[accountProblemExt]().
+		WithStatus(http.StatusForbidden).
+		WithDetail("insufficient funds").
+		WithExtension(accountProblemExt{
+			AccountID: "12345",
+			Balance:   30.5,
+			Accounts:  []string{"/account/12345", "true"},
+			Owner:     accountOwner{Name: "007", Age: 42},
+			Flags:     map[string]bool{"frozen": true},
+			Limits:    []accountOwner{{Name: "daily", Age: 1}},
+			Note:      &note,
+			Tags:      map[string]string{"tier": "1"},
+		})
+
+	data, err := xml.MarshalIndent(problem, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal extended problem as xml: %s", err)
+	}
+
+	var decoded ExtendedProblem[accountProblemExt]
+	if err := xml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal extended problem from xml: %s", err)
+	}
+
+	if !reflect.DeepEqual(*problem, decoded) {
+		t.Errorf("extended problem did not round trip:\ngot\n%#+v\nwant\n%#+v", decoded, *problem)
+	}
+}
+
+func TestRawProblem_UnmarshalXML(t *testing.T) {
+	data := `<problem xmlns="urn:ietf:rfc:7807">
+  <type>https://example.com/probs/out-of-credit</type>
+  <title>You do not have enough credit.</title>
+  <status>403</status>
+  <balance>30</balance>
+  <accounts>
+    <i>https://example.net/account/12345</i>
+    <i>https://example.net/account/67890</i>
+  </accounts>
+  <owner><name>Jane</name></owner>
+  <empty/>
+</problem>`
+
+	var problem RawProblem
+	if err := xml.Unmarshal([]byte(data), &problem); err != nil {
+		t.Fatalf("failed to unmarshal raw problem from xml: %s", err)
+	}
+
+	if problem.Status != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, problem.Status)
+	}
+
+	expect := map[string]json.RawMessage{
+		"balance":  json.RawMessage(`30`),
+		"accounts": json.RawMessage(`["https://example.net/account/12345","https://example.net/account/67890"]`),
+		"owner":    json.RawMessage(`{"name":"Jane"}`),
+		"empty":    json.RawMessage(`null`),
+	}
+	if !reflect.DeepEqual(problem.Extensions, expect) {
+		t.Errorf("unexpected extensions: %v", problem.Extensions)
+	}
+}
+
+func TestValidProblem_MarshalXML(t *testing.T) {
+	problem := NewExt[creditProblemExt]().
+		WithStatus(http.StatusUnauthorized).
+		WithDetail(unAuthDetails).
+		WithExtension(creditProblemExt{Balance: 30})
+
+	valid, err := problem.Validate()
+	if err != nil {
+		t.Fatalf("problem is not valid: %s", err)
+	}
+
+	expect, err := xml.Marshal(problem)
+	if err != nil {
+		t.Fatalf("failed to marshal extended problem as xml: %s", err)
+	}
+
+	validated, err := xml.Marshal(valid)
+	if err != nil {
+		t.Fatalf("failed to marshal valid extended problem as xml: %s", err)
+	}
+
+	if string(expect) != string(validated) {
+		t.Errorf("extended problem does not match validated:\ngot\n%s\nwant\n%s", validated, expect)
+	}
+
+	plain, err := xml.Marshal(&valid.ValidProblem)
+	if err != nil {
+		t.Fatalf("failed to marshal valid problem as xml: %s", err)
+	}
+
+	var decoded Problem
+	if err := xml.Unmarshal(plain, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal valid problem xml: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, problem.Problem) {
+		t.Errorf("valid problem did not round trip:\ngot\n%#+v\nwant\n%#+v", decoded, problem.Problem)
+	}
+}